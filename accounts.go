@@ -10,61 +10,103 @@ import (
 
 // DeriveGridAccountPDA derives the PDA for a grid account
 func (c *Client) DeriveGridAccountPDA(grid solana.PublicKey) (solana.PublicKey, uint8, error) {
-	seeds := [][]byte{
+	return c.findProgramAddress(gridAccountSeeds(grid))
+}
+
+// DeriveProducerAccountPDA derives the PDA for a producer account
+func (c *Client) DeriveProducerAccountPDA(producer solana.PublicKey) (solana.PublicKey, uint8, error) {
+	return c.findProgramAddress(producerAccountSeeds(producer))
+}
+
+// DeriveConsumerAccountPDA derives the PDA for a consumer account
+func (c *Client) DeriveConsumerAccountPDA(consumer solana.PublicKey) (solana.PublicKey, uint8, error) {
+	return c.findProgramAddress(consumerAccountSeeds(consumer))
+}
+
+// DeriveMintRecordPDA derives the PDA for a mint record
+func (c *Client) DeriveMintRecordPDA(producer solana.PublicKey, amount uint64, energyType EnergyType) (solana.PublicKey, uint8, error) {
+	return c.findProgramAddress(mintRecordSeeds(producer, amount, energyType))
+}
+
+// DeriveListingAccountPDA derives the PDA for a listing account
+func (c *Client) DeriveListingAccountPDA(producer solana.PublicKey, amount, priceLamports uint64, energyType EnergyType) (solana.PublicKey, uint8, error) {
+	return c.findProgramAddress(listingAccountSeeds(producer, amount, priceLamports, energyType))
+}
+
+// Fast paths for when the bump is already known
+
+// DeriveGridAccountPDAWithBump computes the grid account PDA from a known bump
+func (c *Client) DeriveGridAccountPDAWithBump(grid solana.PublicKey, bump uint8) (solana.PublicKey, error) {
+	return c.createProgramAddress(gridAccountSeeds(grid), bump)
+}
+
+// DeriveProducerAccountPDAWithBump computes the producer account PDA from a known bump
+func (c *Client) DeriveProducerAccountPDAWithBump(producer solana.PublicKey, bump uint8) (solana.PublicKey, error) {
+	return c.createProgramAddress(producerAccountSeeds(producer), bump)
+}
+
+// DeriveConsumerAccountPDAWithBump computes the consumer account PDA from a known bump
+func (c *Client) DeriveConsumerAccountPDAWithBump(consumer solana.PublicKey, bump uint8) (solana.PublicKey, error) {
+	return c.createProgramAddress(consumerAccountSeeds(consumer), bump)
+}
+
+// DeriveMintRecordPDAWithBump computes the mint record PDA from a known bump
+func (c *Client) DeriveMintRecordPDAWithBump(producer solana.PublicKey, amount uint64, energyType EnergyType, bump uint8) (solana.PublicKey, error) {
+	return c.createProgramAddress(mintRecordSeeds(producer, amount, energyType), bump)
+}
+
+// DeriveListingAccountPDAWithBump computes the listing account PDA from a known bump
+func (c *Client) DeriveListingAccountPDAWithBump(producer solana.PublicKey, amount, priceLamports uint64, energyType EnergyType, bump uint8) (solana.PublicKey, error) {
+	return c.createProgramAddress(listingAccountSeeds(producer, amount, priceLamports, energyType), bump)
+}
+
+// PDA seeds for all account types
+
+func gridAccountSeeds(grid solana.PublicKey) [][]byte {
+	return [][]byte{
 		[]byte("grid"),
 		grid.Bytes(),
 	}
-	return solana.FindProgramAddress(seeds, c.programID)
 }
 
-// DeriveProducerAccountPDA derives the PDA for a producer account
-func (c *Client) DeriveProducerAccountPDA(producer solana.PublicKey) (solana.PublicKey, uint8, error) {
-	seeds := [][]byte{
+func producerAccountSeeds(producer solana.PublicKey) [][]byte {
+	return [][]byte{
 		[]byte("producer"),
 		producer.Bytes(),
 	}
-	return solana.FindProgramAddress(seeds, c.programID)
 }
 
-// DeriveConsumerAccountPDA derives the PDA for a consumer account
-func (c *Client) DeriveConsumerAccountPDA(consumer solana.PublicKey) (solana.PublicKey, uint8, error) {
-	seeds := [][]byte{
+func consumerAccountSeeds(consumer solana.PublicKey) [][]byte {
+	return [][]byte{
 		[]byte("consumer"),
 		consumer.Bytes(),
 	}
-	return solana.FindProgramAddress(seeds, c.programID)
 }
 
-// DeriveMintRecordPDA derives the PDA for a mint record
-func (c *Client) DeriveMintRecordPDA(producer solana.PublicKey, amount uint64, energyType uint8) (solana.PublicKey, uint8, error) {
-	amountBytes := make([]byte, 8)
-	binary.LittleEndian.PutUint64(amountBytes, amount)
-
-	seeds := [][]byte{
+func mintRecordSeeds(producer solana.PublicKey, amount uint64, energyType EnergyType) [][]byte {
+	return [][]byte{
 		[]byte("mint"),
 		producer.Bytes(),
-		amountBytes,
-		{energyType},
+		uint64Bytes(amount),
+		{byte(energyType)},
 	}
-	return solana.FindProgramAddress(seeds, c.programID)
 }
 
-// DeriveListingAccountPDA derives the PDA for a listing account
-func (c *Client) DeriveListingAccountPDA(producer solana.PublicKey, amount, priceLamports uint64, energyType uint8) (solana.PublicKey, uint8, error) {
-	amountBytes := make([]byte, 8)
-	binary.LittleEndian.PutUint64(amountBytes, amount)
-
-	priceBytes := make([]byte, 8)
-	binary.LittleEndian.PutUint64(priceBytes, priceLamports)
-
-	seeds := [][]byte{
+func listingAccountSeeds(producer solana.PublicKey, amount, priceLamports uint64, energyType EnergyType) [][]byte {
+	return [][]byte{
 		[]byte("listing"),
 		producer.Bytes(),
-		amountBytes,
-		priceBytes,
-		{energyType},
+		uint64Bytes(amount),
+		uint64Bytes(priceLamports),
+		{byte(energyType)},
 	}
-	return solana.FindProgramAddress(seeds, c.programID)
+}
+
+// uint64Bytes encodes a PDA seed value as 8 little-endian bytes
+func uint64Bytes(value uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, value)
+	return b
 }
 
 // Account size constants for space calculation
@@ -91,8 +133,8 @@ const (
 // Helper functions for account validation
 
 // IsValidEnergyType checks if the energy type is valid
-func IsValidEnergyType(energyType uint8) bool {
-	return energyType <= uint8(EnergyTypeOther)
+func IsValidEnergyType(energyType EnergyType) bool {
+	return energyType <= EnergyTypeOther
 }
 
 // ValidatePublicKey checks if a public key is valid (not zero)
@@ -100,14 +142,14 @@ func ValidatePublicKey(pubkey solana.PublicKey) bool {
 	return !pubkey.IsZero()
 }
 
-// ValidateAmount checks if an amount is valid (greater than zero)
+// ValidateAmount checks if an amount is within MinEnergyAmount and MaxEnergyAmount
 func ValidateAmount(amount uint64) bool {
-	return amount > 0
+	return amount >= MinEnergyAmount && amount <= MaxEnergyAmount
 }
 
-// ValidatePrice checks if a price is valid (greater than zero)
+// ValidatePrice checks if a price is within MinPriceLamports and MaxPriceLamports
 func ValidatePrice(priceLamports uint64) bool {
-	return priceLamports > 0
+	return priceLamports >= MinPriceLamports && priceLamports <= MaxPriceLamports
 }
 
 // Account creation helpers
@@ -130,19 +172,24 @@ type ConsumerAccountCreationParams struct {
 	Authority solana.PublicKey
 }
 
-// MintRecordCreationParams holds parameters for mint record creation
+// MintRecordCreationParams holds parameters for mint record creation. Use
+// NewMintParams to build them from an Energy.
 type MintRecordCreationParams struct {
-	Grid          solana.PublicKey
-	Producer      solana.PublicKey
+	Grid     solana.PublicKey
+	Producer solana.PublicKey
+	// Amount is a number of tokens of TokenUnit (1 kWh) each
 	Amount        uint64
-	EnergyType    uint8
+	EnergyType    EnergyType
 	GridAuthority solana.PublicKey
 }
 
-// ListingAccountCreationParams holds parameters for listing account creation
+// ListingAccountCreationParams holds parameters for listing account creation.
+// Use NewListingParams to build them from an Energy and a Price.
 type ListingAccountCreationParams struct {
-	Producer      solana.PublicKey
-	Amount        uint64
+	Producer solana.PublicKey
+	// Amount is a number of tokens of TokenUnit (1 kWh) each
+	Amount uint64
+	// PriceLamports is the total price of the whole listing, not a price per token
 	PriceLamports uint64
-	EnergyType    uint8
+	EnergyType    EnergyType
 }
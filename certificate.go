@@ -0,0 +1,391 @@
+package zonnegosdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// CertificateSchema identifies the certificate format and is the first line
+// of every certificate's signed message
+const CertificateSchema = "zonne-certificate/v1"
+
+// EnergyCarrierElectricity is the energy carrier of Zonne certificates
+const EnergyCarrierElectricity = "electricity"
+
+// ErrCertificateSignature is returned by Certificate.Verify when the
+// signature does not match the certificate
+var ErrCertificateSignature = errors.New("invalid certificate signature")
+
+// errUndecodableTransaction marks a fetched transaction whose instructions
+// could not be decoded
+var errUndecodableTransaction = errors.New("undecodable transaction")
+
+// Certificate is a renewable energy certificate for one mint record: proof,
+// signed by the grid authority that minted it, that a producer's generation
+// was issued as energy tokens. The granular fields follow the EnergyTag
+// granular certificate scheme. Certificates can be verified offline with
+// Verify.
+type Certificate struct {
+	Schema string `json:"schema"`
+	// CertificateID is the mint record account, unique per mint
+	CertificateID solana.PublicKey `json:"certificate_id"`
+	// Issuer is the grid authority that signed the mint and the certificate
+	Issuer    solana.PublicKey `json:"issuer"`
+	IssuedAt  time.Time        `json:"issued_at"`
+	ProgramID solana.PublicKey `json:"program_id"`
+	Grid      solana.PublicKey `json:"grid"`
+	Producer  solana.PublicKey `json:"producer"`
+
+	EnergyCarrier  string     `json:"energy_carrier"`
+	EnergySource   EnergyType `json:"energy_source"`
+	EnergyVolumeWh uint64     `json:"energy_volume_wh"`
+	// Tokens is the amount minted, one token per TokenUnit
+	Tokens uint64 `json:"tokens"`
+	// ProductionStart and ProductionEnd bound the generation when the issuer
+	// knows it, such as the interval of the metered readings. The mint record
+	// itself only holds the time of minting.
+	ProductionStart *time.Time `json:"production_start,omitempty"`
+	ProductionEnd   *time.Time `json:"production_end,omitempty"`
+
+	MintedAt    time.Time        `json:"minted_at"`
+	Transaction solana.Signature `json:"transaction"`
+	Slot        uint64           `json:"slot"`
+	Signature   solana.Signature `json:"signature"`
+}
+
+// CertificateParams selects the mint record to certify
+type CertificateParams struct {
+	MintRecordSeeds
+	// ProductionStart and ProductionEnd optionally bound the generation the
+	// mint covers. Set both or neither.
+	ProductionStart time.Time
+	ProductionEnd   time.Time
+}
+
+// MintTransaction is the transaction whose MintEnergyTokens instruction
+// created a mint record
+type MintTransaction struct {
+	Signature     solana.Signature `json:"signature"`
+	Slot          uint64           `json:"slot"`
+	BlockTime     time.Time        `json:"block_time"`
+	GridAuthority solana.PublicKey `json:"grid_authority"`
+}
+
+// CertificateExport is the outcome of ExportCertificates
+type CertificateExport struct {
+	Certificates []*Certificate `json:"certificates"`
+	// Skipped are the transactions of the period that could not be decoded
+	// and may hold mints without a certificate
+	Skipped []SkippedTransaction `json:"skipped,omitempty"`
+}
+
+// SkippedTransaction is a transaction left out of an export and the reason
+type SkippedTransaction struct {
+	Signature solana.Signature `json:"signature"`
+	Reason    string           `json:"reason"`
+}
+
+// NewCertificate builds the unsigned certificate of a mint record created by
+// the transaction. Times are kept to the second, as in the signed message.
+func NewCertificate(programID, mintRecord solana.PublicKey, record MintRecord, transaction solana.Signature, slot uint64) (*Certificate, error) {
+	if !record.EnergyType.IsValid() {
+		return nil, fmt.Errorf("mint record %s: invalid energy type %d", mintRecord, uint8(record.EnergyType))
+	}
+	energy, err := record.Energy()
+	if err != nil {
+		return nil, fmt.Errorf("mint record %s: %w", mintRecord, err)
+	}
+
+	return &Certificate{
+		Schema:         CertificateSchema,
+		CertificateID:  mintRecord,
+		ProgramID:      programID,
+		Grid:           record.Grid,
+		Producer:       record.Producer,
+		EnergyCarrier:  EnergyCarrierElectricity,
+		EnergySource:   record.EnergyType,
+		EnergyVolumeWh: energy.WattHours(),
+		Tokens:         record.Amount,
+		MintedAt:       record.GetTimestamp().UTC(),
+		Transaction:    transaction,
+		Slot:           slot,
+	}, nil
+}
+
+// SetProductionPeriod records the interval of the certified generation
+func (c *Certificate) SetProductionPeriod(start, end time.Time) error {
+	if !end.After(start) {
+		return fmt.Errorf("invalid production period: %s is not after %s", end, start)
+	}
+	start, end = start.UTC().Truncate(time.Second), end.UTC().Truncate(time.Second)
+	c.ProductionStart, c.ProductionEnd = &start, &end
+	return nil
+}
+
+// Message returns the bytes the issuer signs: the certificate's fields as
+// they appear in its JSON form, strings unquoted, one per line in this order:
+//
+//	schema, certificate_id, issuer, issued_at, program_id, grid, producer,
+//	energy_carrier, energy_source, energy_volume_wh, tokens,
+//	production_start, production_end, minted_at, transaction, slot
+//
+// An omitted production period leaves its lines empty.
+func (c *Certificate) Message() []byte {
+	optionalTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return formatCertificateTime(*t)
+	}
+
+	return []byte(strings.Join([]string{
+		c.Schema,
+		c.CertificateID.String(),
+		c.Issuer.String(),
+		formatCertificateTime(c.IssuedAt),
+		c.ProgramID.String(),
+		c.Grid.String(),
+		c.Producer.String(),
+		c.EnergyCarrier,
+		c.EnergySource.String(),
+		strconv.FormatUint(c.EnergyVolumeWh, 10),
+		strconv.FormatUint(c.Tokens, 10),
+		optionalTime(c.ProductionStart),
+		optionalTime(c.ProductionEnd),
+		formatCertificateTime(c.MintedAt),
+		c.Transaction.String(),
+		strconv.FormatUint(c.Slot, 10),
+	}, "\n"))
+}
+
+// formatCertificateTime formats a time as its JSON form in a certificate
+func formatCertificateTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Sign sets the issuer and issue time and signs the certificate
+func (c *Certificate) Sign(issuer solana.PrivateKey, issuedAt time.Time) error {
+	c.Issuer = issuer.PublicKey()
+	c.IssuedAt = issuedAt.UTC().Truncate(time.Second)
+
+	signature, err := issuer.Sign(c.Message())
+	if err != nil {
+		return fmt.Errorf("failed to sign certificate: %w", err)
+	}
+	c.Signature = signature
+	return nil
+}
+
+// Verify checks that the certificate was signed by the issuer. Pass the grid
+// authority you trust: the signature alone only proves who issued it.
+func (c *Certificate) Verify(issuer solana.PublicKey) error {
+	if c.Schema != CertificateSchema {
+		return fmt.Errorf("unsupported certificate schema %q", c.Schema)
+	}
+	if !c.Issuer.Equals(issuer) {
+		return fmt.Errorf("certificate %s is issued by %s, not %s", c.CertificateID, c.Issuer, issuer)
+	}
+	if !c.Signature.Verify(issuer, c.Message()) {
+		return fmt.Errorf("certificate %s: %w", c.CertificateID, ErrCertificateSignature)
+	}
+	return nil
+}
+
+// WriteJSON writes the certificate as indented JSON
+func (c *Certificate) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
+
+// ReadCertificate reads a certificate in JSON form
+func ReadCertificate(r io.Reader) (*Certificate, error) {
+	var certificate Certificate
+	if err := json.NewDecoder(r).Decode(&certificate); err != nil {
+		return nil, fmt.Errorf("failed to decode certificate: %w", err)
+	}
+	return &certificate, nil
+}
+
+// IssueCertificate issues the signed certificate of a mint record. The
+// issuer must be the grid authority that signed the record's mint.
+func (c *Client) IssueCertificate(ctx context.Context, params CertificateParams, issuer solana.PrivateKey) (*Certificate, error) {
+	seeds := params.MintRecordSeeds
+	record, err := c.GetMintRecord(ctx, seeds.Producer, seeds.Amount, seeds.EnergyType)
+	if err != nil {
+		return nil, err
+	}
+	mint, err := c.FindMintTransaction(ctx, seeds)
+	if err != nil {
+		return nil, err
+	}
+	return c.certify(params, *record, mint, issuer)
+}
+
+// ExportCertificates issues the signed certificates of the producer's mints
+// signed by the issuer with a block time in [from, to), oldest first. Mints
+// by other grid authorities are skipped, and so are transactions that cannot
+// be decoded, which are reported as skipped.
+func (c *Client) ExportCertificates(ctx context.Context, producer solana.PublicKey, from, to time.Time, issuer solana.PrivateKey) (*CertificateExport, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("invalid export period: %s is not after %s", to, from)
+	}
+	producerAccountPDA, _, err := c.DeriveProducerAccountPDA(producer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive producer account PDA: %w", err)
+	}
+	signatures, err := c.signaturesForAddress(ctx, producerAccountPDA, from)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &CertificateExport{}
+	for i := len(signatures) - 1; i >= 0; i-- {
+		signature := signatures[i]
+		if signature.Err != nil || signature.BlockTime == nil {
+			continue
+		}
+		if blockTime := signature.BlockTime.Time(); blockTime.Before(from) || !blockTime.Before(to) {
+			continue
+		}
+
+		mints, err := c.mintTransactions(ctx, signature.Signature)
+		if errors.Is(err, errUndecodableTransaction) {
+			export.Skipped = append(export.Skipped, SkippedTransaction{Signature: signature.Signature, Reason: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, mint := range mints {
+			if !mint.seeds.Producer.Equals(producer) || !mint.GridAuthority.Equals(issuer.PublicKey()) {
+				continue
+			}
+			record, err := c.GetMintRecord(ctx, mint.seeds.Producer, mint.seeds.Amount, mint.seeds.EnergyType)
+			if err != nil {
+				return nil, fmt.Errorf("mint record of %s: %w", signature.Signature, err)
+			}
+			certificate, err := c.certify(CertificateParams{MintRecordSeeds: mint.seeds}, *record, &mint.MintTransaction, issuer)
+			if err != nil {
+				return nil, err
+			}
+			export.Certificates = append(export.Certificates, certificate)
+		}
+	}
+
+	return export, nil
+}
+
+// FindMintTransaction finds the finalized transaction that created the mint
+// record
+func (c *Client) FindMintTransaction(ctx context.Context, seeds MintRecordSeeds) (*MintTransaction, error) {
+	mintRecordPDA, _, err := c.DeriveMintRecordPDA(seeds.Producer, seeds.Amount, seeds.EnergyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive mint record PDA: %w", err)
+	}
+	signatures, err := c.signaturesForAddress(ctx, mintRecordPDA, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(signatures) - 1; i >= 0; i-- {
+		if signatures[i].Err != nil {
+			continue
+		}
+		mints, err := c.mintTransactions(ctx, signatures[i].Signature)
+		if err != nil {
+			return nil, err
+		}
+		for _, mint := range mints {
+			if mint.seeds == seeds {
+				return &mint.MintTransaction, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("mint transaction of mint record %s %w", mintRecordPDA, rpc.ErrNotFound)
+}
+
+// decodedMint is a MintEnergyTokens instruction of a transaction
+type decodedMint struct {
+	MintTransaction
+	seeds MintRecordSeeds
+}
+
+// mintTransactions fetches a finalized transaction and returns its
+// MintEnergyTokens instructions
+func (c *Client) mintTransactions(ctx context.Context, signature solana.Signature) ([]decodedMint, error) {
+	result, err := c.getFinalizedTransaction(ctx, signature)
+	if err != nil {
+		return nil, err
+	}
+	if result.Transaction == nil || result.Meta == nil || result.Meta.Err != nil {
+		return nil, nil
+	}
+	transaction, err := decodeTransactionResult(result)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %v", errUndecodableTransaction, signature, err)
+	}
+	instructions, err := c.DecodeTransaction(transaction)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %v", errUndecodableTransaction, signature, err)
+	}
+
+	var mints []decodedMint
+	for _, instruction := range instructions {
+		args, ok := instruction.Args.(*MintEnergyTokensArgs)
+		if instruction.Name != InstructionMintEnergyTokens || !ok {
+			continue
+		}
+		producer, _ := instruction.Account(RoleProducer)
+		gridAuthority, _ := instruction.Account(RoleGridAuthority)
+		mint := decodedMint{
+			MintTransaction: MintTransaction{
+				Signature:     signature,
+				Slot:          result.Slot,
+				GridAuthority: gridAuthority,
+			},
+			seeds: MintRecordSeeds{Producer: producer, Amount: args.Amount, EnergyType: args.EnergyType},
+		}
+		if result.BlockTime != nil {
+			mint.BlockTime = result.BlockTime.Time().UTC()
+		}
+		mints = append(mints, mint)
+	}
+	return mints, nil
+}
+
+// certify builds and signs the certificate of a mint record, checking that
+// the issuer signed its mint
+func (c *Client) certify(params CertificateParams, record MintRecord, mint *MintTransaction, issuer solana.PrivateKey) (*Certificate, error) {
+	if !mint.GridAuthority.Equals(issuer.PublicKey()) {
+		return nil, fmt.Errorf("issuer %s is not the grid authority %s that signed mint %s", issuer.PublicKey(), mint.GridAuthority, mint.Signature)
+	}
+	mintRecordPDA, _, err := c.DeriveMintRecordPDA(params.Producer, params.Amount, params.EnergyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive mint record PDA: %w", err)
+	}
+
+	certificate, err := NewCertificate(c.programID, mintRecordPDA, record, mint.Signature, mint.Slot)
+	if err != nil {
+		return nil, err
+	}
+	if !params.ProductionStart.IsZero() || !params.ProductionEnd.IsZero() {
+		if err := certificate.SetProductionPeriod(params.ProductionStart, params.ProductionEnd); err != nil {
+			return nil, err
+		}
+	}
+	if err := certificate.Sign(issuer, time.Now()); err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
@@ -0,0 +1,87 @@
+package zonnegosdk
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// signedCertificate returns a certificate signed by the issuer after a JSON
+// round-trip
+func signedCertificate(t *testing.T, issuer solana.PrivateKey) *Certificate {
+	t.Helper()
+	record := MintRecord{
+		Grid:       solana.NewWallet().PublicKey(),
+		Producer:   solana.NewWallet().PublicKey(),
+		Amount:     3,
+		EnergyType: EnergyTypeWind,
+		Timestamp:  time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC).Unix(),
+	}
+	certificate, err := NewCertificate(solana.MustPublicKeyFromBase58(DefaultProgramID), solana.NewWallet().PublicKey(), record, solana.Signature{1}, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := certificate.SetProductionPeriod(time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC), time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if err := certificate.Sign(issuer, time.Date(2024, 6, 2, 0, 0, 0, 500, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := certificate.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadCertificate(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return read
+}
+
+func TestCertificateSignVerify(t *testing.T) {
+	issuer := solana.NewWallet().PrivateKey
+	if err := signedCertificate(t, issuer).Verify(issuer.PublicKey()); err != nil {
+		t.Fatalf("signed certificate does not verify: %v", err)
+	}
+
+	tampered := []struct {
+		name   string
+		tamper func(c *Certificate)
+	}{
+		{"producer", func(c *Certificate) { c.Producer = solana.NewWallet().PublicKey() }},
+		{"energy source", func(c *Certificate) { c.EnergySource = EnergyTypeSolar }},
+		{"energy volume", func(c *Certificate) { c.EnergyVolumeWh++ }},
+		{"tokens", func(c *Certificate) { c.Tokens++ }},
+		{"production end", func(c *Certificate) { end := c.ProductionEnd.Add(time.Hour); c.ProductionEnd = &end }},
+		{"production period removed", func(c *Certificate) { c.ProductionStart, c.ProductionEnd = nil, nil }},
+		{"minted at", func(c *Certificate) { c.MintedAt = c.MintedAt.Add(time.Second) }},
+		{"issued at", func(c *Certificate) { c.IssuedAt = c.IssuedAt.Add(-time.Hour) }},
+		{"slot", func(c *Certificate) { c.Slot++ }},
+	}
+	for _, test := range tampered {
+		certificate := signedCertificate(t, issuer)
+		test.tamper(certificate)
+		if err := certificate.Verify(issuer.PublicKey()); !errors.Is(err, ErrCertificateSignature) {
+			t.Errorf("tampered %s: %v, want %v", test.name, err, ErrCertificateSignature)
+		}
+	}
+
+	// A certificate from another issuer does not verify against the trusted
+	// one, whether or not it claims to be from it
+	other := solana.NewWallet().PrivateKey
+	forged := signedCertificate(t, other)
+	if err := forged.Verify(issuer.PublicKey()); err == nil {
+		t.Error("certificate of another issuer verifies against the trusted issuer")
+	}
+	forged.Issuer = issuer.PublicKey()
+	if err := forged.Verify(issuer.PublicKey()); !errors.Is(err, ErrCertificateSignature) {
+		t.Errorf("certificate claiming the trusted issuer: %v, want %v", err, ErrCertificateSignature)
+	}
+	if err := signedCertificate(t, issuer).Verify(other.PublicKey()); err == nil {
+		t.Error("certificate verifies against an issuer that did not sign it")
+	}
+}
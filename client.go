@@ -12,24 +12,97 @@ import (
 
 // Client represents a client for interacting with the Zonne energy marketplace program
 type Client struct {
-	rpcClient *rpc.Client
-	programID solana.PublicKey
+	rpcClient      *rpc.Client
+	programID      solana.PublicKey
+	rpcEndpoint    string
+	wsEndpoint     string
+	confirmation   ConfirmationStrategy
+	commitment     rpc.CommitmentType
+	discriminators map[string][8]byte
+	pdaCache       *PDACache
+	// optionErr is the first error reported by a ClientOption
+	optionErr error
 }
 
-// NewClient creates a new Zonne SDK client
-func NewClient(rpcEndpoint, programID string) *Client {
-	return &Client{
-		rpcClient: rpc.New(rpcEndpoint),
-		programID: solana.MustPublicKeyFromBase58(programID),
+// ClientOption configures optional Client behaviour
+type ClientOption func(*Client)
+
+// WithConfirmationStrategy selects how SendAndConfirmTransaction waits for confirmation
+func WithConfirmationStrategy(strategy ConfirmationStrategy) ClientOption {
+	return func(c *Client) {
+		c.confirmation = strategy
+	}
+}
+
+// WithWebsocketEndpoint sets the websocket endpoint used for subscriptions.
+// When not set, it is derived from the RPC endpoint.
+func WithWebsocketEndpoint(wsEndpoint string) ClientOption {
+	return func(c *Client) {
+		c.wsEndpoint = wsEndpoint
+	}
+}
+
+// WithCommitment sets the commitment used when fetching accounts. When not
+// set, the RPC node's default commitment is used.
+func WithCommitment(commitment rpc.CommitmentType) ClientOption {
+	return func(c *Client) {
+		c.commitment = commitment
 	}
 }
 
-// NewClientWithCustomProgram creates a new client with a custom program ID
-func NewClientWithCustomProgram(rpcEndpoint string, programID solana.PublicKey) *Client {
-	return &Client{
-		rpcClient: rpc.New(rpcEndpoint),
-		programID: programID,
+// NewClient creates a new Zonne SDK client. It panics if programID is not a
+// valid public key or an option is invalid; use NewClientChecked or
+// NewClientFromProfile to get an error instead.
+func NewClient(rpcEndpoint, programID string, opts ...ClientOption) *Client {
+	return mustClient(newClient(rpcEndpoint, solana.MustPublicKeyFromBase58(programID), opts))
+}
+
+// NewClientChecked creates a new Zonne SDK client, returning an error if
+// programID is not a valid public key or an option is invalid
+func NewClientChecked(rpcEndpoint, programID string, opts ...ClientOption) (*Client, error) {
+	if rpcEndpoint == "" {
+		return nil, fmt.Errorf("RPC endpoint must be set")
+	}
+	programKey, err := solana.PublicKeyFromBase58(programID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid program ID: %w", err)
 	}
+	return newClient(rpcEndpoint, programKey, opts)
+}
+
+// NewClientWithCustomProgram creates a new client with a custom program ID.
+// It panics if an option is invalid.
+func NewClientWithCustomProgram(rpcEndpoint string, programID solana.PublicKey, opts ...ClientOption) *Client {
+	return mustClient(newClient(rpcEndpoint, programID, opts))
+}
+
+// mustClient panics if the client could not be created
+func mustClient(c *Client, err error) *Client {
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func newClient(rpcEndpoint string, programID solana.PublicKey, opts []ClientOption) (*Client, error) {
+	c := &Client{
+		rpcClient:      rpc.New(rpcEndpoint),
+		programID:      programID,
+		rpcEndpoint:    rpcEndpoint,
+		confirmation:   ConfirmationPolling,
+		discriminators: defaultDiscriminators(),
+		pdaCache:       NewPDACache(DefaultPDACacheSize),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.optionErr != nil {
+		return nil, c.optionErr
+	}
+	if c.wsEndpoint == "" {
+		c.wsEndpoint = DeriveWebsocketEndpoint(rpcEndpoint)
+	}
+	return c, nil
 }
 
 // GetRPCClient returns the underlying RPC client
@@ -42,6 +115,28 @@ func (c *Client) GetProgramID() solana.PublicKey {
 	return c.programID
 }
 
+// GetWebsocketEndpoint returns the websocket endpoint used for subscriptions
+func (c *Client) GetWebsocketEndpoint() string {
+	return c.wsEndpoint
+}
+
+// GetCommitment returns the commitment used when fetching accounts, empty
+// for the RPC node's default
+func (c *Client) GetCommitment() rpc.CommitmentType {
+	return c.commitment
+}
+
+// getAccountInfo fetches an account with the client's commitment
+func (c *Client) getAccountInfo(ctx context.Context, address solana.PublicKey) (*rpc.GetAccountInfoResult, error) {
+	if c.commitment == "" {
+		return c.rpcClient.GetAccountInfo(ctx, address)
+	}
+	return c.rpcClient.GetAccountInfoWithOpts(ctx, address, &rpc.GetAccountInfoOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: c.commitment,
+	})
+}
+
 // Account fetching methods
 
 // GetGridAccount fetches a grid account
@@ -51,13 +146,13 @@ func (c *Client) GetGridAccount(ctx context.Context, gridPubkey solana.PublicKey
 		return nil, fmt.Errorf("failed to derive grid account PDA: %w", err)
 	}
 
-	accountInfo, err := c.rpcClient.GetAccountInfo(ctx, gridAccountPDA)
+	accountInfo, err := c.getAccountInfo(ctx, gridAccountPDA)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get grid account info: %w", err)
 	}
 
 	if accountInfo.Value == nil {
-		return nil, fmt.Errorf("grid account not found")
+		return nil, fmt.Errorf("grid account %w", rpc.ErrNotFound)
 	}
 
 	var gridAccount GridAccount
@@ -75,13 +170,13 @@ func (c *Client) GetProducerAccount(ctx context.Context, producerPubkey solana.P
 		return nil, fmt.Errorf("failed to derive producer account PDA: %w", err)
 	}
 
-	accountInfo, err := c.rpcClient.GetAccountInfo(ctx, producerAccountPDA)
+	accountInfo, err := c.getAccountInfo(ctx, producerAccountPDA)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get producer account info: %w", err)
 	}
 
 	if accountInfo.Value == nil {
-		return nil, fmt.Errorf("producer account not found")
+		return nil, fmt.Errorf("producer account %w", rpc.ErrNotFound)
 	}
 
 	var producerAccount ProducerAccount
@@ -99,13 +194,13 @@ func (c *Client) GetConsumerAccount(ctx context.Context, consumerPubkey solana.P
 		return nil, fmt.Errorf("failed to derive consumer account PDA: %w", err)
 	}
 
-	accountInfo, err := c.rpcClient.GetAccountInfo(ctx, consumerAccountPDA)
+	accountInfo, err := c.getAccountInfo(ctx, consumerAccountPDA)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get consumer account info: %w", err)
 	}
 
 	if accountInfo.Value == nil {
-		return nil, fmt.Errorf("consumer account not found")
+		return nil, fmt.Errorf("consumer account %w", rpc.ErrNotFound)
 	}
 
 	var consumerAccount ConsumerAccount
@@ -116,20 +211,44 @@ func (c *Client) GetConsumerAccount(ctx context.Context, consumerPubkey solana.P
 	return &consumerAccount, nil
 }
 
+// ProducerAccountExists reports whether the producer account of a wallet
+// has been initialized
+func (c *Client) ProducerAccountExists(ctx context.Context, producerPubkey solana.PublicKey) (bool, error) {
+	producerAccountPDA, _, err := c.DeriveProducerAccountPDA(producerPubkey)
+	if err != nil {
+		return false, fmt.Errorf("failed to derive producer account PDA: %w", err)
+	}
+	return c.accountExists(ctx, producerAccountPDA)
+}
+
+// ConsumerAccountExists reports whether the consumer account of a wallet
+// has been initialized
+func (c *Client) ConsumerAccountExists(ctx context.Context, consumerPubkey solana.PublicKey) (bool, error) {
+	consumerAccountPDA, _, err := c.DeriveConsumerAccountPDA(consumerPubkey)
+	if err != nil {
+		return false, fmt.Errorf("failed to derive consumer account PDA: %w", err)
+	}
+	return c.accountExists(ctx, consumerAccountPDA)
+}
+
 // GetListingAccount fetches a listing account
-func (c *Client) GetListingAccount(ctx context.Context, producer solana.PublicKey, amount, priceLamports uint64, energyType uint8) (*ListingAccount, error) {
+func (c *Client) GetListingAccount(ctx context.Context, producer solana.PublicKey, amount, priceLamports uint64, energyType EnergyType) (*ListingAccount, error) {
 	listingAccountPDA, _, err := c.DeriveListingAccountPDA(producer, amount, priceLamports, energyType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to derive listing account PDA: %w", err)
 	}
 
-	accountInfo, err := c.rpcClient.GetAccountInfo(ctx, listingAccountPDA)
+	return c.getListingAccount(ctx, listingAccountPDA)
+}
+
+func (c *Client) getListingAccount(ctx context.Context, listingAccountPDA solana.PublicKey) (*ListingAccount, error) {
+	accountInfo, err := c.getAccountInfo(ctx, listingAccountPDA)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get listing account info: %w", err)
 	}
 
 	if accountInfo.Value == nil {
-		return nil, fmt.Errorf("listing account not found")
+		return nil, fmt.Errorf("listing account %w", rpc.ErrNotFound)
 	}
 
 	var listingAccount ListingAccount
@@ -141,19 +260,19 @@ func (c *Client) GetListingAccount(ctx context.Context, producer solana.PublicKe
 }
 
 // GetMintRecord fetches a mint record
-func (c *Client) GetMintRecord(ctx context.Context, producer solana.PublicKey, amount uint64, energyType uint8) (*MintRecord, error) {
+func (c *Client) GetMintRecord(ctx context.Context, producer solana.PublicKey, amount uint64, energyType EnergyType) (*MintRecord, error) {
 	mintRecordPDA, _, err := c.DeriveMintRecordPDA(producer, amount, energyType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to derive mint record PDA: %w", err)
 	}
 
-	accountInfo, err := c.rpcClient.GetAccountInfo(ctx, mintRecordPDA)
+	accountInfo, err := c.getAccountInfo(ctx, mintRecordPDA)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get mint record info: %w", err)
 	}
 
 	if accountInfo.Value == nil {
-		return nil, fmt.Errorf("mint record not found")
+		return nil, fmt.Errorf("mint record %w", rpc.ErrNotFound)
 	}
 
 	var mintRecord MintRecord
@@ -164,12 +283,71 @@ func (c *Client) GetMintRecord(ctx context.Context, producer solana.PublicKey, a
 	return &mintRecord, nil
 }
 
+// GetMintRecords fetches every mint record of a producer
+func (c *Client) GetMintRecords(ctx context.Context, producer solana.PublicKey) ([]MintRecord, error) {
+	discriminator := ComputeAccountDiscriminator("MintRecord")
+	accounts, err := c.rpcClient.GetProgramAccountsWithOpts(ctx, c.programID, &rpc.GetProgramAccountsOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: c.commitment,
+		Filters: []rpc.RPCFilter{
+			{DataSize: MintRecordSize},
+			{Memcmp: &rpc.RPCFilterMemcmp{Offset: 0, Bytes: discriminator[:]}},
+			// The producer follows the grid
+			{Memcmp: &rpc.RPCFilterMemcmp{Offset: AccountDiscriminatorSize + 32, Bytes: producer.Bytes()}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mint records: %w", err)
+	}
+
+	records := make([]MintRecord, len(accounts))
+	for i, account := range accounts {
+		if err := borsh.Deserialize(&records[i], account.Account.Data.GetBinary()[AccountDiscriminatorSize:]); err != nil {
+			return nil, fmt.Errorf("failed to deserialize mint record %s: %w", account.Pubkey, err)
+		}
+	}
+	return records, nil
+}
+
 // Transaction building and sending helper
 func (c *Client) SendTransaction(ctx context.Context, transaction *solana.Transaction, signers []solana.PrivateKey) (solana.Signature, error) {
+	if err := c.signTransaction(ctx, transaction, signers); err != nil {
+		return solana.Signature{}, err
+	}
+
+	// Send transaction
+	sig, err := c.rpcClient.SendTransaction(ctx, transaction)
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	return sig, nil
+}
+
+// SimulateTransaction signs a transaction with a fresh blockhash and simulates
+// it without sending. A failed simulation is reported in the result's Err.
+func (c *Client) SimulateTransaction(ctx context.Context, transaction *solana.Transaction, signers []solana.PrivateKey) (*rpc.SimulateTransactionResult, error) {
+	if err := c.signTransaction(ctx, transaction, signers); err != nil {
+		return nil, err
+	}
+
+	result, err := c.rpcClient.SimulateTransactionWithOpts(ctx, transaction, &rpc.SimulateTransactionOpts{
+		SigVerify:  true,
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate transaction: %w", err)
+	}
+
+	return result.Value, nil
+}
+
+// signTransaction sets the latest blockhash and signs the transaction
+func (c *Client) signTransaction(ctx context.Context, transaction *solana.Transaction, signers []solana.PrivateKey) error {
 	// Get latest blockhash
 	latest, err := c.rpcClient.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
 	if err != nil {
-		return solana.Signature{}, fmt.Errorf("failed to get latest blockhash: %w", err)
+		return fmt.Errorf("failed to get latest blockhash: %w", err)
 	}
 
 	transaction.Message.RecentBlockhash = latest.Value.Blockhash
@@ -184,40 +362,103 @@ func (c *Client) SendTransaction(ctx context.Context, transaction *solana.Transa
 		return nil
 	})
 	if err != nil {
-		return solana.Signature{}, fmt.Errorf("failed to sign transaction: %w", err)
+		return fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
-	// Send transaction
-	sig, err := c.rpcClient.SendTransaction(ctx, transaction)
-	if err != nil {
-		return solana.Signature{}, fmt.Errorf("failed to send transaction: %w", err)
-	}
-
-	return sig, nil
+	return nil
 }
 
 // SendAndConfirmTransaction sends a transaction and waits for confirmation
+// using the client's confirmation strategy
 func (c *Client) SendAndConfirmTransaction(ctx context.Context, transaction *solana.Transaction, signers []solana.PrivateKey) (solana.Signature, error) {
 	sig, err := c.SendTransaction(ctx, transaction, signers)
 	if err != nil {
 		return solana.Signature{}, err
 	}
 
-	// Wait for confirmation by polling transaction status
-	for i := 0; i < 30; i++ { // Try for up to 30 seconds
-		status, err := c.rpcClient.GetSignatureStatuses(ctx, true, sig)
-		if err == nil && len(status.Value) > 0 && status.Value[0] != nil {
-			if status.Value[0].ConfirmationStatus == rpc.ConfirmationStatusFinalized {
+	return sig, c.ConfirmTransaction(ctx, sig)
+}
+
+// ConfirmTransaction waits until a sent transaction is finalized, using the
+// client's confirmation strategy. It returns ErrTransactionFailed if the
+// transaction failed and ErrConfirmationTimeout if it was not finalized in time.
+func (c *Client) ConfirmTransaction(ctx context.Context, sig solana.Signature) error {
+	if c.confirmation == ConfirmationWebsocket {
+		return c.confirmWithWebsocket(ctx, sig)
+	}
+	return c.confirmWithPolling(ctx, sig, time.Now().Add(confirmationTimeout))
+}
+
+// BuildUnsignedTransaction creates a transaction with the latest blockhash
+// for signing elsewhere, e.g. in a wallet. Its signatures are zeroed
+// placeholders, one per required signer, as wallets expect. It also returns
+// the last block height at which the blockhash is valid.
+func (c *Client) BuildUnsignedTransaction(ctx context.Context, payer solana.PublicKey, instructions ...solana.Instruction) (*solana.Transaction, uint64, error) {
+	latest, err := c.rpcClient.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get latest blockhash: %w", err)
+	}
+
+	transaction, err := solana.NewTransaction(instructions, latest.Value.Blockhash, solana.TransactionPayer(payer))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	transaction.Signatures = make([]solana.Signature, transaction.Message.Header.NumRequiredSignatures)
+
+	return transaction, latest.Value.LastValidBlockHeight, nil
+}
+
+// PartialSignTransaction signs a transaction built by BuildUnsignedTransaction
+// with some of its signers, leaving the other signatures to be filled in
+// elsewhere
+func PartialSignTransaction(transaction *solana.Transaction, signers ...solana.PrivateKey) error {
+	numSigners := int(transaction.Message.Header.NumRequiredSignatures)
+	if len(transaction.Signatures) != numSigners {
+		signatures := make([]solana.Signature, numSigners)
+		copy(signatures, transaction.Signatures)
+		transaction.Signatures = signatures
+	}
+
+	message, err := transaction.Message.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to serialize transaction message: %w", err)
+	}
+
+	for _, signer := range signers {
+		index := -1
+		for i, key := range transaction.Message.AccountKeys[:numSigners] {
+			if key.Equals(signer.PublicKey()) {
+				index = i
 				break
 			}
 		}
+		if index < 0 {
+			return fmt.Errorf("%s is not a signer of the transaction", signer.PublicKey())
+		}
 
-		// Wait 1 second before checking again
-		select {
-		case <-ctx.Done():
-			return sig, ctx.Err()
-		case <-time.After(time.Second):
+		signature, err := signer.Sign(message)
+		if err != nil {
+			return fmt.Errorf("failed to sign transaction: %w", err)
 		}
+		transaction.Signatures[index] = signature
+	}
+
+	return nil
+}
+
+// SubmitSignedTransaction verifies the signatures of a transaction signed
+// elsewhere and sends it without confirming
+func (c *Client) SubmitSignedTransaction(ctx context.Context, transaction *solana.Transaction) (solana.Signature, error) {
+	if len(transaction.Signatures) != int(transaction.Message.Header.NumRequiredSignatures) {
+		return solana.Signature{}, fmt.Errorf("transaction has %d signatures, %d required", len(transaction.Signatures), transaction.Message.Header.NumRequiredSignatures)
+	}
+	if err := transaction.VerifySignatures(); err != nil {
+		return solana.Signature{}, fmt.Errorf("invalid transaction signature: %w", err)
+	}
+
+	sig, err := c.rpcClient.SendTransaction(ctx, transaction)
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("failed to send transaction: %w", err)
 	}
 
 	return sig, nil
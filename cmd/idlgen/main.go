@@ -0,0 +1,393 @@
+// Command idlgen generates Go bindings from the Zonne program's Anchor IDL and
+// checks that the hand-written SDK agrees with it.
+//
+// Usage:
+//
+//	idlgen -idl zonne.json -out zonne_generated.go -pkg idl
+//	idlgen -idl zonne.json -out zonne_generated.go -check
+//
+// In check mode nothing is written. The command exits with status 1 when the
+// SDK's discriminators, account sizes, instruction account orderings, argument
+// layouts or event types disagree with the IDL, or when the generated file is
+// out of date.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/akbariandev/zonnegosdk"
+)
+
+func main() {
+	idlPath := flag.String("idl", "zonne.json", "path to the Anchor IDL JSON file")
+	outPath := flag.String("out", "zonne_generated.go", "path of the generated Go file")
+	pkg := flag.String("pkg", "idl", "package name of the generated file")
+	check := flag.Bool("check", false, "verify the SDK and the generated file against the IDL instead of writing")
+	flag.Parse()
+
+	data, err := os.ReadFile(*idlPath)
+	if err != nil {
+		log.Fatalf("failed to read IDL: %v", err)
+	}
+
+	idl, err := zonnegosdk.ParseIDL(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	source, err := generate(idl, *pkg, filepath.Base(*idlPath))
+	if err != nil {
+		log.Fatalf("failed to generate code: %v", err)
+	}
+
+	if !*check {
+		if err := os.WriteFile(*outPath, source, 0o644); err != nil {
+			log.Fatalf("failed to write generated code: %v", err)
+		}
+		return
+	}
+
+	failed := false
+	for _, mismatch := range zonnegosdk.CompareIDL(idl) {
+		fmt.Fprintln(os.Stderr, mismatch)
+		failed = true
+	}
+
+	existing, err := os.ReadFile(*outPath)
+	if err != nil || !bytes.Equal(existing, source) {
+		fmt.Fprintf(os.Stderr, "%s is out of date, run go generate\n", *outPath)
+		failed = true
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// goType maps a primitive IDL type to its Go type
+func goType(field zonnegosdk.IDLField) (string, error) {
+	switch field.TypeName() {
+	case "bool":
+		return "bool", nil
+	case "u8":
+		return "uint8", nil
+	case "i8":
+		return "int8", nil
+	case "u16":
+		return "uint16", nil
+	case "i16":
+		return "int16", nil
+	case "u32":
+		return "uint32", nil
+	case "i32":
+		return "int32", nil
+	case "u64":
+		return "uint64", nil
+	case "i64":
+		return "int64", nil
+	case "f32":
+		return "float32", nil
+	case "f64":
+		return "float64", nil
+	case "string":
+		return "string", nil
+	case "pubkey", "publicKey":
+		return "solana.PublicKey", nil
+	default:
+		return "", fmt.Errorf("unsupported type %s for field %s", string(field.Type), field.Name)
+	}
+}
+
+// goName converts an IDL name to an exported Go identifier
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(zonnegosdk.ToSnakeCase(name), "_") {
+		if part == "" {
+			continue
+		}
+		if part == "id" {
+			b.WriteString("ID")
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+type genField struct {
+	GoName   string
+	GoType   string
+	BorshTag string
+}
+
+type genAccountMeta struct {
+	GoName   string
+	Address  string
+	Writable bool
+	Signer   bool
+}
+
+type genInstruction struct {
+	GoName        string
+	Name          string
+	Discriminator string
+	Args          []genField
+	Accounts      []genAccountMeta
+}
+
+type genStruct struct {
+	GoName        string
+	Name          string
+	Discriminator string
+	Size          int
+	Fields        []genField
+}
+
+type genError struct {
+	GoName string
+	Code   uint32
+	Msg    string
+}
+
+type genData struct {
+	Package      string
+	Source       string
+	Address      string
+	HasArgs      bool
+	Instructions []genInstruction
+	Accounts     []genStruct
+	Events       []genStruct
+	Errors       []genError
+}
+
+func byteList(d [8]byte) string {
+	parts := make([]string, len(d))
+	for i, b := range d {
+		parts[i] = fmt.Sprint(b)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func convertFields(fields []zonnegosdk.IDLField) ([]genField, int, error) {
+	var out []genField
+	size := 0
+	for _, field := range fields {
+		t, err := goType(field)
+		if err != nil {
+			return nil, 0, err
+		}
+		if n, err := zonnegosdk.IDLFieldSize(field.TypeName()); err == nil {
+			size += n
+		}
+		out = append(out, genField{GoName: goName(field.Name), GoType: t, BorshTag: zonnegosdk.ToSnakeCase(field.Name)})
+	}
+	return out, size, nil
+}
+
+// generate renders the Go bindings for an IDL
+func generate(idl *zonnegosdk.IDL, pkg, source string) ([]byte, error) {
+	data := genData{Package: pkg, Source: source, Address: idl.Address}
+
+	for _, instruction := range idl.Instructions {
+		args, _, err := convertFields(instruction.Args)
+		if err != nil {
+			return nil, fmt.Errorf("instruction %s: %w", instruction.Name, err)
+		}
+
+		gen := genInstruction{
+			GoName:        goName(instruction.Name),
+			Name:          zonnegosdk.ToSnakeCase(instruction.Name),
+			Discriminator: byteList(idl.InstructionDiscriminator(instruction)),
+			Args:          args,
+		}
+		for _, account := range instruction.Accounts {
+			gen.Accounts = append(gen.Accounts, genAccountMeta{
+				GoName:   goName(account.Name),
+				Address:  account.Address,
+				Writable: account.IsWritable(),
+				Signer:   account.IsSignerAccount(),
+			})
+		}
+		data.Instructions = append(data.Instructions, gen)
+		data.HasArgs = data.HasArgs || len(args) > 0
+	}
+
+	for _, account := range idl.Accounts {
+		fields, err := idl.AccountFields(account)
+		if err != nil {
+			return nil, err
+		}
+		genFields, size, err := convertFields(fields)
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", account.Name, err)
+		}
+		data.Accounts = append(data.Accounts, genStruct{
+			GoName:        goName(account.Name),
+			Name:          account.Name,
+			Discriminator: byteList(idl.AccountDiscriminator(account)),
+			Size:          size,
+			Fields:        genFields,
+		})
+	}
+
+	for _, event := range idl.Events {
+		fields, err := idl.EventFields(event)
+		if err != nil {
+			return nil, err
+		}
+		genFields, _, err := convertFields(fields)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.Name, err)
+		}
+		data.Events = append(data.Events, genStruct{
+			GoName:        goName(event.Name) + "Event",
+			Name:          event.Name,
+			Discriminator: byteList(idl.EventDiscriminator(event)),
+			Fields:        genFields,
+		})
+	}
+
+	for _, e := range idl.Errors {
+		data.Errors = append(data.Errors, genError{GoName: "Err" + goName(e.Name), Code: e.Code, Msg: e.Msg})
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w\n%s", err, buf.String())
+	}
+	return formatted, nil
+}
+
+var fileTemplate = template.Must(template.New("idl").Parse(`// Code generated by idlgen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if or .HasArgs .Errors}}
+	"fmt"
+{{end}}
+	"github.com/gagliardetto/solana-go"
+{{- if .HasArgs}}
+	"github.com/near/borsh-go"
+{{- end}}
+)
+{{if .Address}}
+// ProgramID is the program address declared by the IDL
+var ProgramID = solana.MustPublicKeyFromBase58("{{.Address}}")
+{{end}}
+// Instruction discriminators
+var (
+{{- range .Instructions}}
+	{{.GoName}}Discriminator = [8]byte{ {{- .Discriminator -}} }
+{{- end}}
+)
+
+// Account discriminators
+var (
+{{- range .Accounts}}
+	{{.GoName}}Discriminator = [8]byte{ {{- .Discriminator -}} }
+{{- end}}
+)
+
+// Event discriminators
+var (
+{{- range .Events}}
+	{{.GoName}}Discriminator = [8]byte{ {{- .Discriminator -}} }
+{{- end}}
+)
+{{range .Instructions}}{{$ix := .}}
+// {{.GoName}}Accounts holds the accounts of the {{.Name}} instruction
+type {{.GoName}}Accounts struct {
+{{- range .Accounts}}{{if not .Address}}
+	{{.GoName}} solana.PublicKey
+{{- end}}{{end}}
+}
+
+// AccountMetas returns the accounts of the {{.Name}} instruction in program order
+func (a {{.GoName}}Accounts) AccountMetas() []*solana.AccountMeta {
+	return []*solana.AccountMeta{
+{{- range .Accounts}}
+		{PublicKey: {{if .Address}}solana.MustPublicKeyFromBase58("{{.Address}}"){{else}}a.{{.GoName}}{{end}}, IsWritable: {{.Writable}}, IsSigner: {{.Signer}}},
+{{- end}}
+	}
+}
+{{if .Args}}
+// {{.GoName}}Args holds the arguments of the {{.Name}} instruction
+type {{.GoName}}Args struct {
+{{- range .Args}}
+	{{.GoName}} {{.GoType}} ` + "`borsh:\"{{.BorshTag}}\"`" + `
+{{- end}}
+}
+
+// New{{.GoName}}Instruction builds the {{.Name}} instruction
+func New{{.GoName}}Instruction(programID solana.PublicKey, accounts {{.GoName}}Accounts, args {{.GoName}}Args) (solana.Instruction, error) {
+	serializedData, err := borsh.Serialize(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize {{.Name}} arguments: %w", err)
+	}
+	data := append({{.GoName}}Discriminator[:], serializedData...)
+	return solana.NewInstruction(programID, accounts.AccountMetas(), data), nil
+}
+{{else}}
+// New{{.GoName}}Instruction builds the {{.Name}} instruction
+func New{{.GoName}}Instruction(programID solana.PublicKey, accounts {{.GoName}}Accounts) (solana.Instruction, error) {
+	data := append([]byte{}, {{.GoName}}Discriminator[:]...)
+	return solana.NewInstruction(programID, accounts.AccountMetas(), data), nil
+}
+{{end}}{{end}}
+// Account sizes, including the 8-byte discriminator
+const (
+{{- range .Accounts}}
+	{{.GoName}}Size = 8 + {{.Size}}
+{{- end}}
+)
+{{range .Accounts}}
+// {{.GoName}} is the {{.Name}} account
+type {{.GoName}} struct {
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}} ` + "`borsh:\"{{.BorshTag}}\"`" + `
+{{- end}}
+}
+{{end}}{{range .Events}}
+// {{.GoName}} is the {{.Name}} event
+type {{.GoName}} struct {
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}} ` + "`borsh:\"{{.BorshTag}}\"`" + `
+{{- end}}
+}
+{{end}}{{if .Errors}}
+// ErrorCode is a custom error code of the program
+type ErrorCode uint32
+
+// Program error codes
+const (
+{{- range .Errors}}
+	{{.GoName}} ErrorCode = {{.Code}}
+{{- end}}
+)
+
+// Error returns the message of the error code
+func (e ErrorCode) Error() string {
+	switch e {
+{{- range .Errors}}
+	case {{.GoName}}:
+		return {{printf "%q" .Msg}}
+{{- end}}
+	default:
+		return fmt.Sprintf("unknown program error %d", uint32(e))
+	}
+}
+{{end}}`))
@@ -0,0 +1,51 @@
+// Command zonne-fakemeter simulates a field meter submitting signed readings
+// to a zonne-oracle, for local testing.
+//
+// Usage:
+//
+//	zonne-fakemeter -meter meter-1 -keypair meter.json [-url http://localhost:8081] [-power 2kWh] [-interval 15m] [-backfill 24h]
+//
+// Register the meter ID and the public key it prints in the oracle's config.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/akbariandev/zonnegosdk/oracle"
+	"github.com/gagliardetto/solana-go"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:8081", "oracle URL")
+	meterID := flag.String("meter", "", "meter ID (required)")
+	keypair := flag.String("keypair", "", "meter keypair file (required)")
+	var power zonnegosdk.Energy
+	flag.TextVar(&power, "power", 2*zonnegosdk.KilowattHour, "energy produced per hour")
+	interval := flag.Duration("interval", 15*time.Minute, "reading interval")
+	backfill := flag.Duration("backfill", 0, "submit the readings of this long before now first")
+	flag.Parse()
+
+	if *meterID == "" || *keypair == "" {
+		log.Fatal("-meter and -keypair are required")
+	}
+	key, err := solana.PrivateKeyFromSolanaKeygenFile(*keypair)
+	if err != nil {
+		log.Fatalf("failed to load meter keypair: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	meter := oracle.NewFakeMeter(*meterID, key, power, *interval)
+	log.Printf("meter %s (public key %s) producing %s per hour, submitting to %s", *meterID, key.PublicKey(), power, *url)
+	if err := meter.Run(ctx, *url, time.Now().Add(-*backfill)); err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatal(err)
+	}
+}
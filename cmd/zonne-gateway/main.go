@@ -0,0 +1,87 @@
+// Command zonne-gateway serves the Zonne REST API of package server.
+//
+// Usage:
+//
+//	zonne-gateway [-addr :8080] [-profile devnet] [-profiles zonne.yaml]
+//	zonne-gateway -openapi > openapi.json
+//
+// The cluster is selected with a profile as in zonnegosdk.LoadProfile, so
+// ZONNE_PROFILE, ZONNE_RPC_URL and the other ZONNE_* variables apply.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/akbariandev/zonnegosdk/server"
+	"github.com/gagliardetto/solana-go"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	profileName := flag.String("profile", "", "profile to use (default $ZONNE_PROFILE, then the config default, then localnet)")
+	profiles := flag.String("profiles", os.Getenv("ZONNE_PROFILES"), "profile config file in YAML, TOML or JSON")
+	printSpec := flag.Bool("openapi", false, "print the OpenAPI document and exit")
+	var solanaPay server.SolanaPayConfig
+	flag.StringVar(&solanaPay.Label, "solana-pay-label", server.DefaultSolanaPayLabel, "label shown by Solana Pay wallets")
+	flag.StringVar(&solanaPay.Icon, "solana-pay-icon", "", "icon URL shown by Solana Pay wallets")
+	consumerAuthority := flag.String("consumer-authority", "", "keypair file that initializes consumer accounts for Solana Pay buyers (default: the buyer)")
+	flag.Parse()
+
+	profile, err := zonnegosdk.LoadProfile(*profiles, *profileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	client, err := zonnegosdk.NewClientFromProfile(profile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *consumerAuthority != "" {
+		if solanaPay.ConsumerAuthority, err = solana.PrivateKeyFromSolanaKeygenFile(*consumerAuthority); err != nil {
+			log.Fatalf("failed to load consumer authority: %v", err)
+		}
+	}
+	handler := server.New(client, server.WithSolanaPay(solanaPay))
+
+	if *printSpec {
+		request, _ := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+		handler.ServeHTTP(&stdoutWriter{header: http.Header{}}, request)
+		return
+	}
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("serving profile %s (%s) on %s", profile.Name, profile.RPCURL, *addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+// stdoutWriter is an http.ResponseWriter that writes the body to stdout
+type stdoutWriter struct {
+	header http.Header
+}
+
+func (w *stdoutWriter) Header() http.Header         { return w.header }
+func (w *stdoutWriter) WriteHeader(int)             {}
+func (w *stdoutWriter) Write(b []byte) (int, error) { return os.Stdout.Write(b) }
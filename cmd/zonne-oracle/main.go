@@ -0,0 +1,93 @@
+// Command zonne-oracle runs the grid oracle of package oracle: it accepts
+// signed meter readings over HTTP and mints energy tokens for them.
+//
+// Usage:
+//
+//	zonne-oracle -config oracle.yaml [-addr :8081] [-profile devnet] [-keypair grid.json]
+//
+// The cluster is selected with a profile as in zonnegosdk.LoadProfile, so
+// ZONNE_PROFILE, ZONNE_RPC_URL and the other ZONNE_* variables apply. The
+// grid authority keypair defaults to the profile's fee payer.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/akbariandev/zonnegosdk/oracle"
+	"github.com/gagliardetto/solana-go"
+)
+
+func main() {
+	addr := flag.String("addr", ":8081", "address to listen on")
+	configPath := flag.String("config", "", "oracle config file in YAML or TOML (required)")
+	profileName := flag.String("profile", "", "profile to use (default $ZONNE_PROFILE, then the config default, then localnet)")
+	profiles := flag.String("profiles", os.Getenv("ZONNE_PROFILES"), "profile config file in YAML, TOML or JSON")
+	keypair := flag.String("keypair", "", "grid authority keypair file (default the profile's fee payer)")
+	flag.Parse()
+
+	if *configPath == "" {
+		log.Fatal("-config is required")
+	}
+	config, err := oracle.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	profile, err := zonnegosdk.LoadProfile(*profiles, *profileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	client, err := zonnegosdk.NewClientFromProfile(profile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var gridAuthority solana.PrivateKey
+	if *keypair != "" {
+		gridAuthority, err = solana.PrivateKeyFromSolanaKeygenFile(*keypair)
+	} else {
+		gridAuthority, err = profile.LoadFeePayer()
+	}
+	if err != nil {
+		log.Fatalf("failed to load grid authority: %v", err)
+	}
+
+	o, err := oracle.New(client, gridAuthority, *config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer o.Close()
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           o.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		o.Run(ctx)
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("oracle with grid authority %s on profile %s (%s) listening on %s", gridAuthority.PublicKey(), profile.Name, profile.RPCURL, *addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	<-done
+}
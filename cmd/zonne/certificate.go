@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/gagliardetto/solana-go"
+)
+
+// timeFlag is a flag.Value holding an optional RFC3339 time
+type timeFlag struct {
+	time time.Time
+}
+
+func (f *timeFlag) String() string {
+	if f.time.IsZero() {
+		return ""
+	}
+	return f.time.Format(time.RFC3339)
+}
+
+func (f *timeFlag) Set(s string) error {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return fmt.Errorf("invalid time %q: must be RFC3339, e.g. 2024-06-01T00:00:00Z", s)
+	}
+	f.time = t
+	return nil
+}
+
+// exportedCertificate is a certificate written by certificate export
+type exportedCertificate struct {
+	Path        string                  `json:"path"`
+	Certificate *zonnegosdk.Certificate `json:"certificate"`
+}
+
+func runCertificateExport(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("certificate export", false)
+	var (
+		producer                                 publicKeyFlag
+		amount                                   amountFlags
+		energyType                               = zonnegosdk.EnergyTypeSolar
+		from, to, productionStart, productionEnd timeFlag
+		out                                      string
+	)
+	fs.Var(&producer, "producer", "producer whose mints are certified (required)")
+	amount.register(fs)
+	fs.Var(&energyType, "energy-type", "energy type of the mint record selected by -amount or -energy (default solar)")
+	fs.Var(&from, "from", "certify the mints from this time, RFC3339 (default all)")
+	fs.Var(&to, "to", "certify the mints before this time, RFC3339 (default now)")
+	fs.Var(&productionStart, "production-start", "start of the generation certified by a single mint record, RFC3339")
+	fs.Var(&productionEnd, "production-end", "end of the generation certified by a single mint record, RFC3339")
+	fs.StringVar(&out, "out", ".", "directory the certificates are written to")
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	if producer.key == nil {
+		return fmt.Errorf("-producer is required")
+	}
+
+	issuer, err := e.signer()
+	if err != nil {
+		return err
+	}
+	tokens, err := amount.tokens()
+	if err != nil {
+		return err
+	}
+
+	var certificates []*zonnegosdk.Certificate
+	if tokens != 0 {
+		certificate, err := e.client.IssueCertificate(ctx, zonnegosdk.CertificateParams{
+			MintRecordSeeds: zonnegosdk.MintRecordSeeds{Producer: *producer.key, Amount: tokens, EnergyType: energyType},
+			ProductionStart: productionStart.time,
+			ProductionEnd:   productionEnd.time,
+		}, issuer)
+		if err != nil {
+			return err
+		}
+		certificates = append(certificates, certificate)
+	} else {
+		if !productionStart.time.IsZero() || !productionEnd.time.IsZero() {
+			return fmt.Errorf("-production-start and -production-end require -amount or -energy")
+		}
+		if to.time.IsZero() {
+			to.time = time.Now()
+		}
+		export, err := e.client.ExportCertificates(ctx, *producer.key, from.time, to.time, issuer)
+		if err != nil {
+			return err
+		}
+		for _, skipped := range export.Skipped {
+			fmt.Fprintf(os.Stderr, "zonne: warning: skipped %s\n", skipped.Reason)
+		}
+		certificates = export.Certificates
+	}
+
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", out, err)
+	}
+	exported := make([]exportedCertificate, len(certificates))
+	for i, certificate := range certificates {
+		path := filepath.Join(out, certificate.CertificateID.String()+".json")
+		if err := writeCertificate(path, certificate); err != nil {
+			return err
+		}
+		exported[i] = exportedCertificate{Path: path, Certificate: certificate}
+	}
+
+	return e.print(exported, func(w io.Writer) {
+		for _, export := range exported {
+			certificate := export.Certificate
+			fmt.Fprintf(w, "%s: %s %s minted at %s (%s)\n", export.Path, zonnegosdk.Energy(certificate.EnergyVolumeWh), certificate.EnergySource, certificate.MintedAt, certificate.Transaction)
+		}
+		fmt.Fprintf(w, "Exported %d certificates\n", len(exported))
+	})
+}
+
+// writeCertificate writes a certificate file
+func writeCertificate(path string, certificate *zonnegosdk.Certificate) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create certificate: %w", err)
+	}
+	if err := certificate.WriteJSON(file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write certificate: %w", err)
+	}
+	return file.Close()
+}
+
+// verifiedCertificate is the outcome of verifying one certificate file
+type verifiedCertificate struct {
+	Path          string            `json:"path"`
+	CertificateID *solana.PublicKey `json:"certificate_id,omitempty"`
+	Valid         bool              `json:"valid"`
+	Error         string            `json:"error,omitempty"`
+}
+
+func runCertificateVerify(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("certificate verify", false)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: zonne certificate verify -issuer <public-key> <certificate-file>...")
+		fs.PrintDefaults()
+	}
+	var issuer publicKeyFlag
+	fs.Var(&issuer, "issuer", "grid authority expected to have issued the certificates (required)")
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	if issuer.key == nil {
+		return fmt.Errorf("-issuer is required")
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("expected one or more certificate files")
+	}
+
+	results := make([]verifiedCertificate, fs.NArg())
+	invalid := 0
+	for i, path := range fs.Args() {
+		results[i].Path = path
+		certificate, err := readCertificate(path)
+		if err == nil {
+			results[i].CertificateID = &certificate.CertificateID
+			err = certificate.Verify(*issuer.key)
+		}
+		if err != nil {
+			results[i].Error = err.Error()
+			invalid++
+			continue
+		}
+		results[i].Valid = true
+	}
+
+	if err := e.print(results, func(w io.Writer) {
+		for _, result := range results {
+			if result.Valid {
+				fmt.Fprintf(w, "%s: valid certificate %s\n", result.Path, result.CertificateID)
+			} else {
+				fmt.Fprintf(w, "%s: invalid: %s\n", result.Path, result.Error)
+			}
+		}
+	}); err != nil {
+		return err
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d certificates are invalid", invalid, len(results))
+	}
+	return nil
+}
+
+// readCertificate reads a certificate file
+func readCertificate(path string) (*zonnegosdk.Certificate, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open certificate: %w", err)
+	}
+	defer file.Close()
+	return zonnegosdk.ReadCertificate(file)
+}
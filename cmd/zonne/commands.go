@@ -0,0 +1,480 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/gagliardetto/solana-go"
+)
+
+// publicKeyFlag is a flag.Value holding an optional public key
+type publicKeyFlag struct {
+	key *solana.PublicKey
+}
+
+func (f *publicKeyFlag) String() string {
+	if f.key == nil {
+		return ""
+	}
+	return f.key.String()
+}
+
+func (f *publicKeyFlag) Set(s string) error {
+	key, err := solana.PublicKeyFromBase58(s)
+	if err != nil {
+		return err
+	}
+	f.key = &key
+	return nil
+}
+
+// or returns the key, or fallback when the flag was not set
+func (f *publicKeyFlag) or(fallback solana.PublicKey) solana.PublicKey {
+	if f.key == nil {
+		return fallback
+	}
+	return *f.key
+}
+
+// amountFlags selects a token amount with -amount or -energy
+type amountFlags struct {
+	amount uint64
+	energy string
+}
+
+func (a *amountFlags) register(fs *flag.FlagSet) {
+	fs.Uint64Var(&a.amount, "amount", 0, "amount in tokens (1 token = 1 kWh)")
+	fs.StringVar(&a.energy, "energy", "", "amount as energy, e.g. 1.5MWh; alternative to -amount")
+}
+
+func (a *amountFlags) tokens() (uint64, error) {
+	if a.energy == "" {
+		return a.amount, nil
+	}
+	if a.amount != 0 {
+		return 0, fmt.Errorf("-amount and -energy are mutually exclusive")
+	}
+	energy, err := zonnegosdk.ParseEnergy(a.energy)
+	if err != nil {
+		return 0, err
+	}
+	return energy.Tokens()
+}
+
+// priceFlags selects a listing's total price
+type priceFlags struct {
+	lamports  uint64
+	sol       string
+	unitPrice uint64
+}
+
+func (p *priceFlags) register(fs *flag.FlagSet) {
+	fs.Uint64Var(&p.lamports, "price", 0, "total price of the listing in lamports")
+	fs.StringVar(&p.sol, "price-sol", "", "total price of the listing in SOL, e.g. 0.5")
+	fs.Uint64Var(&p.unitPrice, "unit-price", 0, "price per token in lamports")
+}
+
+func (p *priceFlags) total(amount uint64) (uint64, error) {
+	set := 0
+	for _, isSet := range []bool{p.lamports != 0, p.sol != "", p.unitPrice != 0} {
+		if isSet {
+			set++
+		}
+	}
+	if set > 1 {
+		return 0, fmt.Errorf("-price, -price-sol and -unit-price are mutually exclusive")
+	}
+
+	switch {
+	case p.sol != "":
+		return zonnegosdk.ParseSOL(p.sol)
+	case p.unitPrice != 0:
+		return zonnegosdk.ListingPriceForUnits(amount, p.unitPrice)
+	default:
+		return p.lamports, nil
+	}
+}
+
+// parse parses the command line and resolves the environment
+func parse(fs *flag.FlagSet, opts *options, args []string) (*env, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return newEnv(opts)
+}
+
+func runGridInit(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("grid init", true)
+	var grid publicKeyFlag
+	fs.Var(&grid, "grid", "grid public key (default the keypair's public key)")
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+
+	authority, err := e.signer()
+	if err != nil {
+		return err
+	}
+
+	instruction, err := e.client.InitializeGrid(zonnegosdk.GridAccountCreationParams{
+		Grid:      grid.or(authority.PublicKey()),
+		Authority: authority.PublicKey(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.send(ctx, authority, nil, instruction)
+}
+
+func runProducerInit(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("producer init", true)
+	var producer publicKeyFlag
+	fs.Var(&producer, "producer", "producer public key (default the keypair's public key)")
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+
+	authority, err := e.signer()
+	if err != nil {
+		return err
+	}
+
+	instruction, err := e.client.InitializeProducer(zonnegosdk.ProducerAccountCreationParams{
+		Producer:  producer.or(authority.PublicKey()),
+		Authority: authority.PublicKey(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.send(ctx, authority, nil, instruction)
+}
+
+func runConsumerInit(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("consumer init", true)
+	var consumer publicKeyFlag
+	fs.Var(&consumer, "consumer", "consumer public key (default the keypair's public key)")
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+
+	authority, err := e.signer()
+	if err != nil {
+		return err
+	}
+
+	instruction, err := e.client.InitializeConsumer(zonnegosdk.ConsumerAccountCreationParams{
+		Consumer:  consumer.or(authority.PublicKey()),
+		Authority: authority.PublicKey(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.send(ctx, authority, nil, instruction)
+}
+
+func runMint(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("mint", true)
+	var (
+		grid, producer publicKeyFlag
+		amount         amountFlags
+		energyType     = zonnegosdk.EnergyTypeSolar
+	)
+	fs.Var(&grid, "grid", "grid public key (default the keypair's public key)")
+	fs.Var(&producer, "producer", "producer to mint to (required)")
+	fs.Var(&energyType, "energy-type", "energy type: solar, wind, hydro or other (default solar)")
+	amount.register(fs)
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	if producer.key == nil {
+		return fmt.Errorf("-producer is required")
+	}
+
+	gridAuthority, err := e.signer()
+	if err != nil {
+		return err
+	}
+
+	tokens, err := amount.tokens()
+	if err != nil {
+		return err
+	}
+
+	params := zonnegosdk.MintRecordCreationParams{
+		Grid:          grid.or(gridAuthority.PublicKey()),
+		Producer:      *producer.key,
+		Amount:        tokens,
+		EnergyType:    energyType,
+		GridAuthority: gridAuthority.PublicKey(),
+	}
+	plan, err := e.client.PlanMintWithProducer(ctx, params, gridAuthority.PublicKey(), zonnegosdk.CollisionFail)
+	if err != nil {
+		return err
+	}
+
+	return e.send(ctx, gridAuthority, plan.Mints[0].Address, plan.Instructions...)
+}
+
+func runList(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("list", true)
+	var (
+		amount     amountFlags
+		price      priceFlags
+		energyType = zonnegosdk.EnergyTypeSolar
+	)
+	fs.Var(&energyType, "energy-type", "energy type: solar, wind, hydro or other (default solar)")
+	amount.register(fs)
+	price.register(fs)
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+
+	producer, err := e.signer()
+	if err != nil {
+		return err
+	}
+
+	tokens, err := amount.tokens()
+	if err != nil {
+		return err
+	}
+	total, err := price.total(tokens)
+	if err != nil {
+		return err
+	}
+
+	params := zonnegosdk.ListingAccountCreationParams{
+		Producer:      producer.PublicKey(),
+		Amount:        tokens,
+		PriceLamports: total,
+		EnergyType:    energyType,
+	}
+	if err := e.client.ValidateListingBalance(ctx, params); err != nil {
+		return err
+	}
+
+	planned, err := e.client.PlanListing(ctx, params, zonnegosdk.CollisionFail)
+	if err != nil {
+		return err
+	}
+
+	return e.send(ctx, producer, planned[0].Ref, planned[0].Instruction)
+}
+
+func runBuy(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("buy", true)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: zonne buy [flags] <listing-ref>")
+		fs.PrintDefaults()
+	}
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	ref, err := e.listingRefArg(fs)
+	if err != nil {
+		return err
+	}
+
+	buyer, err := e.signer()
+	if err != nil {
+		return err
+	}
+
+	plan, err := e.client.PlanPurchase(ctx, buyer.PublicKey(), ref, buyer.PublicKey())
+	if err != nil {
+		return err
+	}
+
+	return e.send(ctx, buyer, nil, plan.Instructions...)
+}
+
+func runCancel(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("cancel", true)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: zonne cancel [flags] <listing-ref>")
+		fs.PrintDefaults()
+	}
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	ref, err := e.listingRefArg(fs)
+	if err != nil {
+		return err
+	}
+
+	producer, err := e.signer()
+	if err != nil {
+		return err
+	}
+	if !producer.PublicKey().Equals(ref.Seeds.Producer) {
+		return fmt.Errorf("listing belongs to %s, not to the keypair %s", ref.Seeds.Producer, producer.PublicKey())
+	}
+
+	instruction, err := e.client.CancelListingByRef(ref)
+	if err != nil {
+		return err
+	}
+
+	return e.send(ctx, producer, nil, instruction)
+}
+
+// listingRefArg parses and verifies the listing reference given as the only argument
+func (e *env) listingRefArg(fs *flag.FlagSet) (zonnegosdk.ListingRef, error) {
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return zonnegosdk.ListingRef{}, fmt.Errorf("expected a listing reference")
+	}
+	ref, err := zonnegosdk.ParseListingRef(fs.Arg(0))
+	if err != nil {
+		return zonnegosdk.ListingRef{}, err
+	}
+	if err := e.client.VerifyListingRef(ref); err != nil {
+		return zonnegosdk.ListingRef{}, err
+	}
+	return ref, nil
+}
+
+func runListings(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("listings", false)
+	var producer publicKeyFlag
+	fs.Var(&producer, "producer", "only show listings of this producer")
+	active := fs.Bool("active", false, "only show active listings")
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+
+	listings, err := e.client.GetListings(ctx, zonnegosdk.ListingFilter{Producer: producer.key, ActiveOnly: *active})
+	if err != nil {
+		return err
+	}
+
+	return e.print(listings, func(w io.Writer) {
+		for _, listing := range listings {
+			status := "active"
+			if !listing.Account.IsActive {
+				status = "closed"
+			}
+			fmt.Fprintf(w, "%s  %-6s %6d tokens  %s SOL  %s\n",
+				listing.Ref, listing.Account.EnergyType, listing.Account.Amount,
+				zonnegosdk.FormatSOL(listing.Account.PriceLamports), status)
+		}
+	})
+}
+
+func runAccountShow(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("account show", false)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: zonne account show [flags] <grid|producer|consumer> <public-key>")
+		fmt.Fprintln(fs.Output(), "       zonne account show [flags] listing <listing-ref>")
+		fs.PrintDefaults()
+	}
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected an account kind and address")
+	}
+
+	kind, address := fs.Arg(0), fs.Arg(1)
+	if kind == "listing" {
+		ref, err := zonnegosdk.ParseListingRef(address)
+		if err != nil {
+			return err
+		}
+		listing, err := e.client.GetListingAccountByRef(ctx, ref)
+		if err != nil {
+			return err
+		}
+		return e.print(listing, func(w io.Writer) {
+			fmt.Fprintf(w, "Listing:     %s\n", ref.Address)
+			fmt.Fprintf(w, "Producer:    %s\n", listing.Producer)
+			fmt.Fprintf(w, "Amount:      %d tokens\n", listing.Amount)
+			fmt.Fprintf(w, "Price:       %s SOL\n", zonnegosdk.FormatSOL(listing.PriceLamports))
+			fmt.Fprintf(w, "Energy Type: %s\n", listing.EnergyType)
+			fmt.Fprintf(w, "Active:      %t\n", listing.IsActive)
+			fmt.Fprintf(w, "Created At:  %s\n", listing.GetCreatedAt().UTC())
+		})
+	}
+
+	key, err := solana.PublicKeyFromBase58(address)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+
+	switch kind {
+	case "grid":
+		grid, err := e.client.GetGridAccount(ctx, key)
+		if err != nil {
+			return err
+		}
+		return e.print(grid, func(w io.Writer) {
+			fmt.Fprintf(w, "Active: %t\n", grid.IsActive)
+		})
+	case "producer":
+		producer, err := e.client.GetProducerAccount(ctx, key)
+		if err != nil {
+			return err
+		}
+		return e.print(producer, func(w io.Writer) {
+			fmt.Fprintf(w, "Balance: %d tokens\n", producer.Balance)
+		})
+	case "consumer":
+		consumer, err := e.client.GetConsumerAccount(ctx, key)
+		if err != nil {
+			return err
+		}
+		return e.print(consumer, func(w io.Writer) {
+			fmt.Fprintf(w, "Consumption: %d tokens\n", consumer.Consumption)
+		})
+	default:
+		return fmt.Errorf("unknown account kind %q: must be grid, producer, consumer or listing", kind)
+	}
+}
+
+func runTxDecode(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("tx decode", false)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: zonne tx decode [flags] <signature>")
+		fs.PrintDefaults()
+	}
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a transaction signature")
+	}
+
+	signature, err := solana.SignatureFromBase58(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	instructions, err := e.client.GetDecodedTransaction(ctx, signature)
+	if err != nil {
+		return err
+	}
+
+	return e.print(instructions, func(w io.Writer) {
+		for _, instruction := range instructions {
+			fmt.Fprint(w, instruction)
+		}
+	})
+}
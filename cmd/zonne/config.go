@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/akbariandev/zonnegosdk/idl"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// clusters maps cluster names accepted by -cluster to RPC endpoints
+var clusters = map[string]string{
+	"localnet":     zonnegosdk.LocalnetRPC,
+	"localhost":    zonnegosdk.LocalnetRPC,
+	"devnet":       zonnegosdk.DevnetRPC,
+	"testnet":      zonnegosdk.TestnetRPC,
+	"mainnet":      zonnegosdk.MainnetRPC,
+	"mainnet-beta": zonnegosdk.MainnetRPC,
+}
+
+// Output formats accepted by -output
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// options holds the flags shared by every command
+type options struct {
+	profile    string
+	profiles   string
+	cluster    string
+	url        string
+	programID  string
+	keypair    string
+	configPath string
+	output     string
+	dryRun     bool
+}
+
+// newFlagSet creates the flag set of a command with the shared flags registered.
+// sends reports whether the command sends a transaction and accepts -dry-run.
+func newFlagSet(name string, sends bool) (*flag.FlagSet, *options) {
+	fs := flag.NewFlagSet("zonne "+name, flag.ContinueOnError)
+	opts := &options{}
+
+	fs.StringVar(&opts.profile, "profile", "", "profile to use, from -profiles or the built-in profiles")
+	fs.StringVar(&opts.profiles, "profiles", os.Getenv("ZONNE_PROFILES"), "profile config file in YAML, TOML or JSON (default $ZONNE_PROFILES)")
+	fs.StringVar(&opts.cluster, "cluster", "", "cluster to use: localnet, devnet, testnet or mainnet")
+	fs.StringVar(&opts.url, "url", "", "RPC endpoint, overrides -cluster")
+	fs.StringVar(&opts.programID, "program-id", "", "Zonne program ID (default from the IDL)")
+	fs.StringVar(&opts.keypair, "keypair", "", "Solana CLI keypair file (default from the Solana CLI config)")
+	fs.StringVar(&opts.configPath, "config", "", "Solana CLI config file (default ~/.config/solana/cli/config.yml)")
+	fs.StringVar(&opts.output, "output", outputText, "output format: text or json")
+	if sends {
+		fs.BoolVar(&opts.dryRun, "dry-run", false, "simulate the transaction instead of sending it")
+	}
+
+	return fs, opts
+}
+
+// solanaConfig is the subset of the Solana CLI config file used by zonne
+type solanaConfig struct {
+	JSONRPCURL   string
+	WebsocketURL string
+	KeypairPath  string
+}
+
+// loadSolanaConfig reads a Solana CLI config file. A missing default config
+// is not an error.
+func loadSolanaConfig(path string) (solanaConfig, error) {
+	explicit := path != ""
+	if !explicit {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return solanaConfig{}, nil
+		}
+		path = filepath.Join(home, ".config", "solana", "cli", "config.yml")
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return solanaConfig{}, nil
+	}
+	if err != nil {
+		return solanaConfig{}, fmt.Errorf("failed to open Solana config: %w", err)
+	}
+	defer file.Close()
+
+	var config solanaConfig
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch strings.TrimSpace(key) {
+		case "json_rpc_url":
+			config.JSONRPCURL = value
+		case "websocket_url":
+			config.WebsocketURL = value
+		case "keypair_path":
+			config.KeypairPath = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return solanaConfig{}, fmt.Errorf("failed to read Solana config: %w", err)
+	}
+
+	return config, nil
+}
+
+// env is the resolved environment of a command
+type env struct {
+	client  *zonnegosdk.Client
+	opts    *options
+	keypair string
+	out     io.Writer
+}
+
+// newEnv resolves the endpoint, program ID and keypair path from the flags
+// and the Solana CLI config
+func newEnv(opts *options) (*env, error) {
+	if opts.output != outputText && opts.output != outputJSON {
+		return nil, fmt.Errorf("invalid output format %q: must be text or json", opts.output)
+	}
+
+	config, err := loadSolanaConfig(opts.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := config.JSONRPCURL
+	wsEndpoint := config.WebsocketURL
+	keypair := config.KeypairPath
+	programID := idl.ProgramID
+	var commitment rpc.CommitmentType
+	if opts.profile != "" || opts.profiles != "" || os.Getenv(zonnegosdk.EnvProfile) != "" {
+		profile, err := zonnegosdk.LoadProfile(opts.profiles, opts.profile)
+		if err != nil {
+			return nil, err
+		}
+		endpoint = profile.RPCURL
+		wsEndpoint = profile.WebsocketURL
+		programID = solana.MustPublicKeyFromBase58(profile.ProgramID)
+		if profile.FeePayerKeypair != "" {
+			keypair = profile.FeePayerKeypair
+		}
+		commitment = profile.Commitment
+	}
+	// The websocket endpoint belongs to the RPC endpoint it was configured
+	// with, so overriding the endpoint derives it again
+	if opts.cluster != "" {
+		var ok bool
+		if endpoint, ok = clusters[opts.cluster]; !ok {
+			return nil, fmt.Errorf("unknown cluster %q", opts.cluster)
+		}
+		wsEndpoint = ""
+	}
+	if opts.url != "" {
+		endpoint = opts.url
+		wsEndpoint = ""
+	}
+	if endpoint == "" {
+		endpoint = zonnegosdk.LocalnetRPC
+	}
+
+	if opts.programID != "" {
+		if programID, err = solana.PublicKeyFromBase58(opts.programID); err != nil {
+			return nil, fmt.Errorf("invalid program ID: %w", err)
+		}
+	}
+
+	if opts.keypair != "" {
+		keypair = opts.keypair
+	}
+	if keypair == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			keypair = filepath.Join(home, ".config", "solana", "id.json")
+		}
+	}
+
+	var clientOpts []zonnegosdk.ClientOption
+	if wsEndpoint != "" {
+		clientOpts = append(clientOpts, zonnegosdk.WithWebsocketEndpoint(wsEndpoint))
+	}
+	if commitment != "" {
+		clientOpts = append(clientOpts, zonnegosdk.WithCommitment(commitment))
+	}
+
+	return &env{
+		client:  zonnegosdk.NewClientWithCustomProgram(endpoint, programID, clientOpts...),
+		opts:    opts,
+		keypair: keypair,
+		out:     os.Stdout,
+	}, nil
+}
+
+// signer loads the keypair used to sign and pay for transactions
+func (e *env) signer() (solana.PrivateKey, error) {
+	if e.keypair == "" {
+		return nil, fmt.Errorf("no keypair configured, use -keypair")
+	}
+	key, err := solana.PrivateKeyFromSolanaKeygenFile(e.keypair)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load keypair %s: %w", e.keypair, err)
+	}
+	return key, nil
+}
+
+// print writes v as JSON, or calls text for the text output format
+func (e *env) print(v interface{}, text func(w io.Writer)) error {
+	if e.opts.output == outputJSON {
+		encoder := json.NewEncoder(e.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+	text(e.out)
+	return nil
+}
+
+// sendResult is the outcome of sending or simulating a transaction
+type sendResult struct {
+	Signature     string      `json:"signature,omitempty"`
+	Simulated     bool        `json:"simulated"`
+	Err           interface{} `json:"err,omitempty"`
+	Logs          []string    `json:"logs,omitempty"`
+	UnitsConsumed *uint64     `json:"units_consumed,omitempty"`
+	Extra         interface{} `json:"result,omitempty"`
+}
+
+// send signs the instructions with the keypair, which also pays the fees,
+// and sends them, or simulates them with -dry-run. extra is included in the
+// output, e.g. the reference of a new listing.
+func (e *env) send(ctx context.Context, signer solana.PrivateKey, extra interface{}, instructions ...solana.Instruction) error {
+	transaction, err := solana.NewTransaction(instructions, solana.Hash{}, solana.TransactionPayer(signer.PublicKey()))
+	if err != nil {
+		return fmt.Errorf("failed to create transaction: %w", err)
+	}
+	signers := []solana.PrivateKey{signer}
+
+	if e.opts.dryRun {
+		simulation, err := e.client.SimulateTransaction(ctx, transaction, signers)
+		if err != nil {
+			return err
+		}
+
+		result := sendResult{Simulated: true, Err: simulation.Err, Logs: simulation.Logs, UnitsConsumed: simulation.UnitsConsumed, Extra: extra}
+		if err := e.print(result, func(w io.Writer) {
+			for _, line := range simulation.Logs {
+				fmt.Fprintln(w, line)
+			}
+			if simulation.UnitsConsumed != nil {
+				fmt.Fprintf(w, "Compute units: %d\n", *simulation.UnitsConsumed)
+			}
+			printExtra(w, extra)
+		}); err != nil {
+			return err
+		}
+		if simulation.Err != nil {
+			return fmt.Errorf("simulation failed: %v", simulation.Err)
+		}
+		return nil
+	}
+
+	signature, err := e.client.SendAndConfirmTransaction(ctx, transaction, signers)
+	if err != nil {
+		return err
+	}
+
+	return e.print(sendResult{Signature: signature.String(), Extra: extra}, func(w io.Writer) {
+		fmt.Fprintf(w, "Signature: %s\n", signature)
+		printExtra(w, extra)
+	})
+}
+
+// printExtra writes the extra result of a command in text form
+func printExtra(w io.Writer, extra interface{}) {
+	if extra == nil {
+		return
+	}
+	if stringer, ok := extra.(fmt.Stringer); ok {
+		fmt.Fprintf(w, "Result: %s\n", stringer)
+		return
+	}
+	fmt.Fprintf(w, "Result: %v\n", extra)
+}
@@ -0,0 +1,112 @@
+// Command zonne operates the Zonne energy marketplace from the command line.
+//
+// Usage:
+//
+//	zonne <command> [flags] [arguments]
+//
+// Commands:
+//
+//	grid init           initialize a grid account
+//	producer init       initialize a producer account
+//	consumer init       initialize a consumer account
+//	mint                mint energy tokens to a producer
+//	list                list energy tokens for sale
+//	buy                 buy a listing
+//	cancel              cancel a listing
+//	listings            show listings
+//	account show        show a grid, producer, consumer or listing account
+//	tx decode           decode the Zonne instructions of a transaction
+//	meter ingest        mint energy tokens from producer meter readings
+//	consumption ingest  mint consumption tokens from consumer meter readings
+//	certificate export  export signed renewable energy certificates of mint records
+//	certificate verify  verify certificates offline against the issuer's public key
+//
+// Every command accepts the connection flags -profile, -profiles, -cluster,
+// -url, -program-id, -keypair, -config and -output. Defaults are read from the
+// Solana CLI config file (~/.config/solana/cli/config.yml), or from a Zonne
+// profile when -profile, -profiles or ZONNE_PROFILE is set. -cluster, -url,
+// -program-id and -keypair override the selected profile. Commands that send a transaction
+// also accept -dry-run, which simulates the transaction instead. For meter
+// ingest and consumption ingest, -dry-run prints the planned mints.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+)
+
+// command is a zonne subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{"grid init", "initialize a grid account", runGridInit},
+	{"producer init", "initialize a producer account", runProducerInit},
+	{"consumer init", "initialize a consumer account", runConsumerInit},
+	{"mint", "mint energy tokens to a producer", runMint},
+	{"list", "list energy tokens for sale", runList},
+	{"buy", "buy a listing", runBuy},
+	{"cancel", "cancel a listing", runCancel},
+	{"listings", "show listings", runListings},
+	{"account show", "show a grid, producer, consumer or listing account", runAccountShow},
+	{"tx decode", "decode the Zonne instructions of a transaction", runTxDecode},
+	{"meter ingest", "mint energy tokens from producer meter readings", runMeterIngest},
+	{"consumption ingest", "mint consumption tokens from consumer meter readings", runConsumptionIngest},
+	{"certificate export", "export signed renewable energy certificates of mint records", runCertificateExport},
+	{"certificate verify", "verify certificates offline against the issuer's public key", runCertificateVerify},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "zonne:", err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches to the command named by the leading arguments
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage()
+		return flag.ErrHelp
+	}
+
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == cmd.name {
+			return cmd.run(ctx, args[len(words):])
+		}
+	}
+
+	usage()
+	return fmt.Errorf("unknown command %q", strings.Join(args, " "))
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: zonne <command> [flags] [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+
+	sorted := append([]command(nil), commands...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	for _, cmd := range sorted {
+		fmt.Fprintf(os.Stderr, "  %-18s %s\n", cmd.name, cmd.summary)
+	}
+
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'zonne <command> -h' for the flags of a command.")
+}
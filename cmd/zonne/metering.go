@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/akbariandev/zonnegosdk/metering"
+	"github.com/gagliardetto/solana-go"
+)
+
+// Reading file formats accepted by -format
+const (
+	formatCSV         = "csv"
+	formatGreenButton = "greenbutton"
+)
+
+// watermarkFlag collects repeated -watermark <wallet>/<meter>=<time> flags
+type watermarkFlag map[string]time.Time
+
+func (f watermarkFlag) String() string {
+	var watermarks []string
+	for key, end := range f {
+		value := "new"
+		if !end.IsZero() {
+			value = end.Format(time.RFC3339)
+		}
+		watermarks = append(watermarks, key+"="+value)
+	}
+	sort.Strings(watermarks)
+	return strings.Join(watermarks, ",")
+}
+
+func (f watermarkFlag) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	wallet, meterID, hasMeter := strings.Cut(key, "/")
+	if !ok || !hasMeter || meterID == "" {
+		return fmt.Errorf("invalid watermark %q: must be <wallet>/<meter>=<time>", s)
+	}
+	if _, err := solana.PublicKeyFromBase58(wallet); err != nil {
+		return fmt.Errorf("invalid watermark %q: %w", s, err)
+	}
+	if value == "new" {
+		f[key] = time.Time{}
+		return nil
+	}
+	end, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return fmt.Errorf("invalid watermark %q: time must be RFC3339, e.g. 2024-06-01T00:00:00Z, or new", s)
+	}
+	f[key] = end
+	return nil
+}
+
+// meterFlags are the flags shared by the meter ingestion commands
+type meterFlags struct {
+	grid       publicKeyFlag
+	checkpoint string
+	format     string
+	wallet     publicKeyFlag
+	meterID    string
+	energyType string
+	authority  string
+}
+
+func (m *meterFlags) register(fs *flag.FlagSet, opts *options, role string) {
+	fs.Var(&m.grid, "grid", "grid public key (default the keypair's public key)")
+	fs.StringVar(&m.checkpoint, "checkpoint", "", "checkpoint file (required)")
+	fs.StringVar(&m.format, "format", "", "reading file format: csv or greenbutton (default from the file extension)")
+	fs.Var(&m.wallet, role, role+" of readings without a "+role+" column")
+	fs.StringVar(&m.meterID, "meter", "", "meter ID of readings without a meter_id column")
+	fs.StringVar(&m.authority, role+"-authority", "", "keypair file that initializes missing "+role+" accounts (default the keypair)")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "print the planned mints instead of sending them")
+}
+
+// source returns the source of readings that do not name their wallet,
+// meter or energy type
+func (m *meterFlags) source() (metering.Source, error) {
+	source := metering.Source{MeterID: m.meterID}
+	if m.wallet.key != nil {
+		source.Wallet = *m.wallet.key
+	}
+	if m.energyType != "" {
+		energyType, err := zonnegosdk.ParseEnergyTypeStrict(m.energyType)
+		if err != nil {
+			return metering.Source{}, err
+		}
+		source.EnergyType = energyType
+	}
+	return source, nil
+}
+
+// authorityKey loads the -<role>-authority keypair, or returns nil when unset
+func (m *meterFlags) authorityKey() (solana.PrivateKey, error) {
+	if m.authority == "" {
+		return nil, nil
+	}
+	key, err := solana.PrivateKeyFromSolanaKeygenFile(m.authority)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load keypair %s: %w", m.authority, err)
+	}
+	return key, nil
+}
+
+// readings parses the reading files. consumption selects which Green Button
+// flow direction is read.
+func (m *meterFlags) readings(files []string, consumption bool) ([]metering.Reading, error) {
+	if m.checkpoint == "" {
+		return nil, fmt.Errorf("-checkpoint is required")
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("expected one or more reading files")
+	}
+	source, err := m.source()
+	if err != nil {
+		return nil, err
+	}
+
+	var readings []metering.Reading
+	for _, path := range files {
+		format := m.format
+		if format == "" {
+			format = formatCSV
+			if strings.EqualFold(filepath.Ext(path), ".xml") {
+				format = formatGreenButton
+			}
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open readings: %w", err)
+		}
+		var parsed []metering.Reading
+		switch {
+		case format == formatCSV:
+			parsed, err = metering.ParseCSV(file, source)
+		case format == formatGreenButton && consumption:
+			parsed, err = metering.ParseGreenButtonConsumption(file, source)
+		case format == formatGreenButton:
+			parsed, err = metering.ParseGreenButton(file, source)
+		default:
+			err = fmt.Errorf("invalid format %q: must be csv or greenbutton", format)
+		}
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		readings = append(readings, parsed...)
+	}
+
+	return readings, nil
+}
+
+func runMeterIngest(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("meter ingest", false)
+	var m meterFlags
+	m.register(fs, opts, "producer")
+	fs.StringVar(&m.energyType, "energy-type", "", "energy type of readings without an energy_type column: solar, wind, hydro or other")
+	watermarks := make(watermarkFlag)
+	fs.Var(watermarks, "watermark", "`wallet/meter=time` of the end of the last minted reading of a meter not in the checkpoint, or new if nothing was minted (repeatable)")
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+
+	readings, err := m.readings(fs.Args(), false)
+	if err != nil {
+		return err
+	}
+	gridAuthority, err := e.signer()
+	if err != nil {
+		return err
+	}
+	producerAuthority, err := m.authorityKey()
+	if err != nil {
+		return err
+	}
+
+	pipeline, err := metering.NewPipeline(e.client, metering.Config{
+		Grid:              m.grid.or(gridAuthority.PublicKey()),
+		GridAuthority:     gridAuthority,
+		ProducerAuthority: producerAuthority,
+		Checkpoint:        metering.NewFileCheckpointStore(m.checkpoint),
+		Watermarks:        watermarks,
+	})
+	if err != nil {
+		return err
+	}
+
+	run := pipeline.Ingest
+	if e.opts.dryRun {
+		run = pipeline.Plan
+	}
+	report, runErr := run(ctx, readings)
+	if err := e.print(report, func(w io.Writer) { printMeterReport(w, report) }); err != nil {
+		return err
+	}
+	return runErr
+}
+
+// printMeterReport writes a meter ingestion report in text form
+func printMeterReport(w io.Writer, report *metering.Report) {
+	if report.Recovered != nil {
+		fmt.Fprintf(w, "Recovered mint %s\n", report.Recovered.Signature)
+	}
+	for _, batch := range report.Batches {
+		fmt.Fprintf(w, "%s %s: %s from %d readings, mint %d tokens, carry %s", batch.Producer, batch.EnergyType, batch.Energy, len(batch.Readings), batch.Tokens, batch.Carry)
+		if len(batch.Mints) > 1 {
+			fmt.Fprintf(w, " split into %d mint records", len(batch.Mints))
+		}
+		if batch.InitializedProducer {
+			fmt.Fprint(w, " with producer account initialization")
+		}
+		if batch.Signature != nil {
+			fmt.Fprintf(w, " (%s)", batch.Signature)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "Skipped %d duplicate readings\n", len(report.Duplicates))
+}
+
+func runConsumptionIngest(ctx context.Context, args []string) error {
+	fs, opts := newFlagSet("consumption ingest", false)
+	var m meterFlags
+	m.register(fs, opts, "consumer")
+	e, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+
+	readings, err := m.readings(fs.Args(), true)
+	if err != nil {
+		return err
+	}
+	gridAuthority, err := e.signer()
+	if err != nil {
+		return err
+	}
+	consumerAuthority, err := m.authorityKey()
+	if err != nil {
+		return err
+	}
+
+	bridge, err := metering.NewConsumptionBridge(e.client, metering.ConsumptionConfig{
+		Grid:              m.grid.or(gridAuthority.PublicKey()),
+		GridAuthority:     gridAuthority,
+		ConsumerAuthority: consumerAuthority,
+		Checkpoint:        metering.NewFileCheckpointStore(m.checkpoint),
+	})
+	if err != nil {
+		return err
+	}
+
+	run := bridge.Ingest
+	if e.opts.dryRun {
+		run = bridge.Plan
+	}
+	report, runErr := run(ctx, readings)
+	if err := e.print(report, func(w io.Writer) {
+		for _, mint := range report.Mints {
+			fmt.Fprintf(w, "%s: metered %s, covered %d tokens, mint %d tokens", mint.Consumer, mint.Metered, mint.Covered, mint.Amount)
+			if mint.InitializesConsumer {
+				fmt.Fprint(w, " with consumer account initialization")
+			}
+			if mint.Signature != nil {
+				fmt.Fprintf(w, " (%s)", mint.Signature)
+			}
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Skipped %d duplicate readings\n", len(report.Duplicates))
+	}); err != nil {
+		return err
+	}
+	return runErr
+}
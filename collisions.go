@@ -0,0 +1,265 @@
+package zonnegosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ErrSeedCollision is returned when the PDA a listing or mint would create already exists
+var ErrSeedCollision = errors.New("seed collision: account already exists")
+
+// maxCollisionAttempts bounds the number of alternative seeds tried by a collision strategy
+const maxCollisionAttempts = 16
+
+// CollisionStrategy selects how a seed collision is resolved
+type CollisionStrategy int
+
+const (
+	// CollisionFail returns ErrSeedCollision
+	CollisionFail CollisionStrategy = iota
+	// CollisionPerturbPrice raises the listing price one lamport at a time
+	// until the listing PDA is unused. Listings only.
+	CollisionPerturbPrice
+	// CollisionSplit splits the amount into two parts whose PDAs are both
+	// unused, preserving the total. A listing's price is divided between the
+	// parts in proportion to their amounts. Listings and mints.
+	CollisionSplit
+)
+
+// ListingSeeds are the seeds of a listing account PDA
+type ListingSeeds struct {
+	Producer      solana.PublicKey `json:"producer"`
+	Amount        uint64           `json:"amount"`
+	PriceLamports uint64           `json:"price_lamports"`
+	EnergyType    EnergyType       `json:"energy_type"`
+}
+
+// MintRecordSeeds are the seeds of a mint record PDA
+type MintRecordSeeds struct {
+	Producer   solana.PublicKey `json:"producer"`
+	Amount     uint64           `json:"amount"`
+	EnergyType EnergyType       `json:"energy_type"`
+}
+
+// PlannedListing is a ListTokensForSale instruction together with the
+// reference of the listing it creates
+type PlannedListing struct {
+	Ref         ListingRef
+	Instruction solana.Instruction
+}
+
+// PlannedMint is a MintEnergyTokens instruction together with the seeds of
+// the mint record it creates
+type PlannedMint struct {
+	Seeds       MintRecordSeeds
+	Address     solana.PublicKey
+	Instruction solana.Instruction
+}
+
+// PlanListing builds the instructions for a listing after checking that its
+// PDA is unused. On collision the strategy decides whether to fail or to
+// choose alternative seeds; the references returned carry the seeds actually
+// used so the listings can later be bought or cancelled.
+func (c *Client) PlanListing(ctx context.Context, params ListingAccountCreationParams, strategy CollisionStrategy) ([]PlannedListing, error) {
+	seeds := ListingSeeds{
+		Producer:      params.Producer,
+		Amount:        params.Amount,
+		PriceLamports: params.PriceLamports,
+		EnergyType:    params.EnergyType,
+	}
+
+	free, err := c.listingSeedsFree(ctx, seeds)
+	if err != nil {
+		return nil, err
+	}
+	if free {
+		return c.planListings(seeds)
+	}
+
+	switch strategy {
+	case CollisionPerturbPrice:
+		for i := 1; i <= maxCollisionAttempts; i++ {
+			candidate := seeds
+			candidate.PriceLamports = seeds.PriceLamports + uint64(i)
+			if candidate.PriceLamports < seeds.PriceLamports {
+				break
+			}
+			if free, err := c.listingSeedsFree(ctx, candidate); err != nil {
+				return nil, err
+			} else if free {
+				return c.planListings(candidate)
+			}
+		}
+	case CollisionSplit:
+		for i := 1; i <= maxCollisionAttempts && uint64(i) < seeds.Amount; i++ {
+			first, second := seeds, seeds
+			first.Amount = seeds.Amount - uint64(i)
+			second.Amount = uint64(i)
+			if first.Amount == second.Amount {
+				continue
+			}
+			first.PriceLamports = splitPrice(seeds.PriceLamports, first.Amount, seeds.Amount)
+			second.PriceLamports = seeds.PriceLamports - first.PriceLamports
+			if !ValidatePrice(first.PriceLamports) || !ValidatePrice(second.PriceLamports) {
+				continue
+			}
+			firstFree, err := c.listingSeedsFree(ctx, first)
+			if err != nil {
+				return nil, err
+			}
+			secondFree, err := c.listingSeedsFree(ctx, second)
+			if err != nil {
+				return nil, err
+			}
+			if firstFree && secondFree {
+				return c.planListings(first, second)
+			}
+		}
+	case CollisionFail:
+	default:
+		return nil, fmt.Errorf("unsupported collision strategy %d", strategy)
+	}
+
+	address, _, err := c.DeriveListingAccountPDA(seeds.Producer, seeds.Amount, seeds.PriceLamports, seeds.EnergyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive listing account PDA: %w", err)
+	}
+	return nil, fmt.Errorf("listing %s: %w", address, ErrSeedCollision)
+}
+
+// PlanMint builds the instructions for a mint after checking that its mint
+// record PDA is unused. Only CollisionFail and CollisionSplit apply to mints,
+// since any other strategy would change the minted total.
+func (c *Client) PlanMint(ctx context.Context, params MintRecordCreationParams, strategy CollisionStrategy) ([]PlannedMint, error) {
+	seeds := MintRecordSeeds{
+		Producer:   params.Producer,
+		Amount:     params.Amount,
+		EnergyType: params.EnergyType,
+	}
+
+	free, err := c.mintRecordSeedsFree(ctx, seeds)
+	if err != nil {
+		return nil, err
+	}
+	if free {
+		return c.planMints(params, seeds)
+	}
+
+	switch strategy {
+	case CollisionSplit:
+		for i := 1; i <= maxCollisionAttempts && uint64(i) < seeds.Amount; i++ {
+			first, second := seeds, seeds
+			first.Amount = seeds.Amount - uint64(i)
+			second.Amount = uint64(i)
+			if first.Amount == second.Amount {
+				continue
+			}
+			firstFree, err := c.mintRecordSeedsFree(ctx, first)
+			if err != nil {
+				return nil, err
+			}
+			secondFree, err := c.mintRecordSeedsFree(ctx, second)
+			if err != nil {
+				return nil, err
+			}
+			if firstFree && secondFree {
+				return c.planMints(params, first, second)
+			}
+		}
+	case CollisionFail:
+	default:
+		return nil, fmt.Errorf("collision strategy %d does not apply to mints", strategy)
+	}
+
+	address, _, err := c.DeriveMintRecordPDA(seeds.Producer, seeds.Amount, seeds.EnergyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive mint record PDA: %w", err)
+	}
+	return nil, fmt.Errorf("mint record %s: %w", address, ErrSeedCollision)
+}
+
+// splitPrice returns the share of a listing price for part of its amount,
+// rounded down
+func splitPrice(priceLamports, part, amount uint64) uint64 {
+	hi, lo := bits.Mul64(priceLamports, part)
+	share, _ := bits.Div64(hi, lo, amount)
+	return share
+}
+
+// planListings builds one listing instruction per set of seeds
+func (c *Client) planListings(seeds ...ListingSeeds) ([]PlannedListing, error) {
+	var planned []PlannedListing
+	for _, s := range seeds {
+		instruction, ref, err := c.CreateListing(ListingAccountCreationParams{
+			Producer:      s.Producer,
+			Amount:        s.Amount,
+			PriceLamports: s.PriceLamports,
+			EnergyType:    s.EnergyType,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		planned = append(planned, PlannedListing{Ref: ref, Instruction: instruction})
+	}
+	return planned, nil
+}
+
+// planMints builds one mint instruction per set of seeds
+func (c *Client) planMints(params MintRecordCreationParams, seeds ...MintRecordSeeds) ([]PlannedMint, error) {
+	var planned []PlannedMint
+	for _, s := range seeds {
+		mintParams := params
+		mintParams.Amount = s.Amount
+
+		instruction, err := c.MintEnergyTokens(mintParams)
+		if err != nil {
+			return nil, err
+		}
+
+		address, _, err := c.DeriveMintRecordPDA(s.Producer, s.Amount, s.EnergyType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive mint record PDA: %w", err)
+		}
+
+		planned = append(planned, PlannedMint{Seeds: s, Address: address, Instruction: instruction})
+	}
+	return planned, nil
+}
+
+// listingSeedsFree reports whether the listing PDA for the seeds is unused
+func (c *Client) listingSeedsFree(ctx context.Context, seeds ListingSeeds) (bool, error) {
+	address, _, err := c.DeriveListingAccountPDA(seeds.Producer, seeds.Amount, seeds.PriceLamports, seeds.EnergyType)
+	if err != nil {
+		return false, fmt.Errorf("failed to derive listing account PDA: %w", err)
+	}
+	exists, err := c.accountExists(ctx, address)
+	return !exists, err
+}
+
+// mintRecordSeedsFree reports whether the mint record PDA for the seeds is unused
+func (c *Client) mintRecordSeedsFree(ctx context.Context, seeds MintRecordSeeds) (bool, error) {
+	address, _, err := c.DeriveMintRecordPDA(seeds.Producer, seeds.Amount, seeds.EnergyType)
+	if err != nil {
+		return false, fmt.Errorf("failed to derive mint record PDA: %w", err)
+	}
+	exists, err := c.accountExists(ctx, address)
+	return !exists, err
+}
+
+// accountExists reports whether an account exists at the address
+func (c *Client) accountExists(ctx context.Context, address solana.PublicKey) (bool, error) {
+	accountInfo, err := c.getAccountInfo(ctx, address)
+	if errors.Is(err, rpc.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get account info for %s: %w", address, err)
+	}
+	return accountInfo.Value != nil, nil
+}
@@ -0,0 +1,167 @@
+package zonnegosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// ConfirmationStrategy selects how transaction confirmation is awaited
+type ConfirmationStrategy int
+
+const (
+	// ConfirmationPolling polls GetSignatureStatuses once per second
+	ConfirmationPolling ConfirmationStrategy = iota
+	// ConfirmationWebsocket waits on a signatureSubscribe notification and
+	// falls back to polling if the websocket cannot be used
+	ConfirmationWebsocket
+)
+
+// Confirmation timing
+const (
+	confirmationTimeout      = 30 * time.Second
+	confirmationPollInterval = time.Second
+)
+
+// ErrConfirmationTimeout is returned when a transaction is not finalized
+// within the confirmation timeout. The transaction may still land later.
+var ErrConfirmationTimeout = errors.New("transaction confirmation timed out")
+
+// ErrTransactionFailed is returned when a transaction was executed but failed
+var ErrTransactionFailed = errors.New("transaction failed")
+
+// String returns the name of the confirmation strategy
+func (s ConfirmationStrategy) String() string {
+	switch s {
+	case ConfirmationPolling:
+		return "polling"
+	case ConfirmationWebsocket:
+		return "websocket"
+	default:
+		return "unknown"
+	}
+}
+
+// DeriveWebsocketEndpoint derives the websocket endpoint of a cluster from its
+// RPC endpoint. The scheme is switched to ws/wss and, following the Solana
+// validator convention, an explicit port is incremented by one (8899 -> 8900).
+func DeriveWebsocketEndpoint(rpcEndpoint string) string {
+	u, err := url.Parse(rpcEndpoint)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+
+	if port := u.Port(); port != "" {
+		if n, err := strconv.Atoi(port); err == nil {
+			u.Host = strings.TrimSuffix(u.Host, ":"+port) + ":" + strconv.Itoa(n+1)
+		}
+	}
+
+	return u.String()
+}
+
+// confirmWithPolling polls the signature status until it is finalized or the
+// deadline passes
+func (c *Client) confirmWithPolling(ctx context.Context, sig solana.Signature, deadline time.Time) error {
+	for time.Now().Before(deadline) {
+		finalized, err := c.isFinalized(ctx, sig)
+		if errors.Is(err, ErrTransactionFailed) {
+			return err
+		}
+		if err == nil && finalized {
+			return nil
+		}
+
+		// Wait before checking again
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(confirmationPollInterval):
+		}
+	}
+
+	return fmt.Errorf("transaction %s: %w", sig, ErrConfirmationTimeout)
+}
+
+// confirmWithWebsocket waits for a signatureSubscribe notification, falling
+// back to polling if the socket cannot be opened or drops before the
+// notification arrives. Polling only uses the time left of the confirmation
+// timeout.
+func (c *Client) confirmWithWebsocket(ctx context.Context, sig solana.Signature) error {
+	deadline := time.Now().Add(confirmationTimeout)
+	if c.wsEndpoint == "" {
+		return c.confirmWithPolling(ctx, sig, deadline)
+	}
+
+	subCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	wsClient, err := ws.Connect(subCtx, c.wsEndpoint)
+	if err != nil {
+		return c.confirmWithPolling(ctx, sig, deadline)
+	}
+	defer wsClient.Close()
+
+	sub, err := wsClient.SignatureSubscribe(sig, rpc.CommitmentFinalized)
+	if err != nil {
+		return c.confirmWithPolling(ctx, sig, deadline)
+	}
+	defer sub.Unsubscribe()
+
+	// The transaction may have been finalized before the subscription was
+	// registered, in which case no notification will be delivered
+	if finalized, err := c.isFinalized(subCtx, sig); errors.Is(err, ErrTransactionFailed) {
+		return err
+	} else if err == nil && finalized {
+		return nil
+	}
+
+	select {
+	case result, ok := <-sub.Response():
+		if !ok || result == nil {
+			return c.confirmWithPolling(ctx, sig, deadline)
+		}
+		if result.Value.Err != nil {
+			return fmt.Errorf("%w: %v", ErrTransactionFailed, result.Value.Err)
+		}
+		return nil
+	case <-sub.Err():
+		return c.confirmWithPolling(ctx, sig, deadline)
+	case <-subCtx.Done():
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return fmt.Errorf("transaction %s: %w", sig, ErrConfirmationTimeout)
+	}
+}
+
+// isFinalized reports whether the signature has reached finalized commitment,
+// returning ErrTransactionFailed if the transaction was executed but failed
+func (c *Client) isFinalized(ctx context.Context, sig solana.Signature) (bool, error) {
+	status, err := c.rpcClient.GetSignatureStatuses(ctx, true, sig)
+	if err != nil {
+		return false, err
+	}
+	if len(status.Value) == 0 || status.Value[0] == nil {
+		return false, nil
+	}
+	if status.Value[0].Err != nil {
+		return false, fmt.Errorf("%w: %v", ErrTransactionFailed, status.Value[0].Err)
+	}
+	return status.Value[0].ConfirmationStatus == rpc.ConfirmationStatusFinalized, nil
+}
@@ -0,0 +1,223 @@
+package zonnegosdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// ConsumptionSource identifies how consumption was added to a consumer account
+type ConsumptionSource string
+
+const (
+	// ConsumptionPurchased is consumption added by BuyTokens
+	ConsumptionPurchased ConsumptionSource = "purchased"
+	// ConsumptionGridMinted is consumption added by MintConsumptionTokens
+	ConsumptionGridMinted ConsumptionSource = "grid_minted"
+)
+
+// ConsumptionEntry is one event that increased a consumer's consumption
+type ConsumptionEntry struct {
+	Time       time.Time         `json:"time"`
+	Signature  solana.Signature  `json:"signature"`
+	Source     ConsumptionSource `json:"source"`
+	ListingID  *solana.PublicKey `json:"listing_id,omitempty"`
+	Producer   *solana.PublicKey `json:"producer,omitempty"`
+	EnergyType *EnergyType       `json:"energy_type,omitempty"`
+	Amount     uint64            `json:"amount"`
+	Lamports   uint64            `json:"lamports"`
+}
+
+// ConsumerReport breaks a consumer's consumption down by source and checks
+// that it adds up to the on-chain ConsumerAccount.Consumption counter
+type ConsumerReport struct {
+	Consumer            solana.PublicKey            `json:"consumer"`
+	Purchased           Energy                      `json:"purchased"`
+	PurchasedByType     map[EnergyType]Energy       `json:"purchased_by_energy_type"`
+	PurchasedByProducer map[solana.PublicKey]Energy `json:"purchased_by_producer"`
+	PurchasedByListing  map[solana.PublicKey]Energy `json:"purchased_by_listing"`
+	LamportsSpent       uint64                      `json:"lamports_spent"`
+	GridMinted          Energy                      `json:"grid_minted"`
+	Entries             []ConsumptionEntry          `json:"entries"`
+
+	// ReplayedConsumption is the sum of every known event, in tokens;
+	// Reconciled reports whether it equals OnChainConsumption
+	ReplayedConsumption uint64   `json:"replayed_consumption"`
+	OnChainConsumption  uint64   `json:"on_chain_consumption"`
+	Reconciled          bool     `json:"reconciled"`
+	Discrepancies       []string `json:"discrepancies,omitempty"`
+}
+
+// GetConsumerReport builds the consumption report of a consumer from the
+// event history of its consumer account. The energy type of each purchase is
+// taken from the listing account, or from its TokensListed event when the
+// account no longer exists.
+func (c *Client) GetConsumerReport(ctx context.Context, consumer solana.PublicKey) (*ConsumerReport, error) {
+	account, err := c.GetConsumerAccount(ctx, consumer)
+	if err != nil {
+		return nil, err
+	}
+
+	consumerAccountPDA, _, err := c.DeriveConsumerAccountPDA(consumer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive consumer account PDA: %w", err)
+	}
+
+	history, err := c.GetEventHistory(ctx, consumerAccountPDA)
+	if err != nil {
+		return nil, err
+	}
+
+	listingTypes := make(map[solana.PublicKey]EnergyType)
+	for _, tx := range history {
+		for _, event := range tx.Events {
+			purchase, ok := event.Data.(*TokensPurchasedEvent)
+			if !ok || !purchase.Buyer.Equals(consumer) {
+				continue
+			}
+			if _, seen := listingTypes[purchase.ListingID]; seen {
+				continue
+			}
+
+			energyType, found, err := c.listingEnergyType(ctx, purchase.ListingID)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				listingTypes[purchase.ListingID] = energyType
+			}
+		}
+	}
+
+	return BuildConsumerReport(consumer, history, listingTypes, account.Consumption)
+}
+
+// BuildConsumerReport builds a consumer report from the consumer account's
+// event history, oldest first, the energy types of purchased listings and the
+// on-chain consumption counter
+func BuildConsumerReport(consumer solana.PublicKey, history []TransactionEvents, listingTypes map[solana.PublicKey]EnergyType, onChainConsumption uint64) (*ConsumerReport, error) {
+	report := &ConsumerReport{
+		Consumer:            consumer,
+		PurchasedByType:     make(map[EnergyType]Energy),
+		PurchasedByProducer: make(map[solana.PublicKey]Energy),
+		PurchasedByListing:  make(map[solana.PublicKey]Energy),
+		OnChainConsumption:  onChainConsumption,
+	}
+
+	for _, tx := range history {
+		for _, event := range tx.Events {
+			entry := ConsumptionEntry{Time: tx.BlockTime, Signature: tx.Signature}
+
+			switch data := event.Data.(type) {
+			case *TokensPurchasedEvent:
+				if !data.Buyer.Equals(consumer) {
+					continue
+				}
+				entry.Source = ConsumptionPurchased
+				entry.ListingID = &data.ListingID
+				entry.Producer = &data.Producer
+				entry.Amount = data.Amount
+				entry.Lamports = data.PriceLamports
+				if energyType, ok := listingTypes[data.ListingID]; ok {
+					entry.EnergyType = &energyType
+				} else {
+					report.flag("purchase %s of listing %s: energy type unknown", tx.Signature, data.ListingID)
+				}
+			case *ConsumptionMintedEvent:
+				if !data.Consumer.Equals(consumer) {
+					continue
+				}
+				entry.Source = ConsumptionGridMinted
+				entry.Amount = data.Amount
+			default:
+				continue
+			}
+
+			if err := report.add(entry); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	report.Reconciled = report.ReplayedConsumption == onChainConsumption
+	if !report.Reconciled {
+		report.flag("replayed consumption %d differs from on-chain consumption %d", report.ReplayedConsumption, onChainConsumption)
+	}
+
+	return report, nil
+}
+
+// add records a consumption entry
+func (r *ConsumerReport) add(entry ConsumptionEntry) error {
+	energy, err := EnergyFromTokens(entry.Amount)
+	if err != nil {
+		return err
+	}
+
+	total := r.ReplayedConsumption + entry.Amount
+	if total < r.ReplayedConsumption {
+		return fmt.Errorf("consumption overflow at %s", entry.Signature)
+	}
+	r.ReplayedConsumption = total
+
+	switch entry.Source {
+	case ConsumptionPurchased:
+		r.Purchased += energy
+		r.PurchasedByProducer[*entry.Producer] += energy
+		r.PurchasedByListing[*entry.ListingID] += energy
+		if entry.EnergyType != nil {
+			r.PurchasedByType[*entry.EnergyType] += energy
+		}
+		r.LamportsSpent += entry.Lamports
+	case ConsumptionGridMinted:
+		r.GridMinted += energy
+	}
+
+	r.Entries = append(r.Entries, entry)
+	return nil
+}
+
+// flag records a discrepancy found while building the report
+func (r *ConsumerReport) flag(format string, args ...interface{}) {
+	r.Discrepancies = append(r.Discrepancies, fmt.Sprintf(format, args...))
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *ConsumerReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// listingEnergyType returns the energy type of a listing from its account,
+// or from its TokensListed event if the account has been closed
+func (c *Client) listingEnergyType(ctx context.Context, listingID solana.PublicKey) (EnergyType, bool, error) {
+	exists, err := c.accountExists(ctx, listingID)
+	if err != nil {
+		return 0, false, err
+	}
+	if exists {
+		listing, err := c.getListingAccount(ctx, listingID)
+		if err != nil {
+			return 0, false, err
+		}
+		return listing.EnergyType, true, nil
+	}
+
+	history, err := c.GetEventHistory(ctx, listingID)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, tx := range history {
+		for _, event := range tx.Events {
+			if listed, ok := event.Data.(*TokensListedEvent); ok && listed.ListingID.Equals(listingID) {
+				return listed.EnergyType, true, nil
+			}
+		}
+	}
+	return 0, false, nil
+}
@@ -0,0 +1,359 @@
+package zonnegosdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/near/borsh-go"
+)
+
+// Instruction names as defined by the Zonne program
+const (
+	InstructionInitializeGrid        = "initialize_grid"
+	InstructionInitializeProducer    = "initialize_producer"
+	InstructionInitializeConsumer    = "initialize_consumer"
+	InstructionMintEnergyTokens      = "mint_energy_tokens"
+	InstructionListTokensForSale     = "list_tokens_for_sale"
+	InstructionCancelListing         = "cancel_listing"
+	InstructionBuyTokens             = "buy_tokens"
+	InstructionMintConsumptionTokens = "mint_consumption_tokens"
+)
+
+// Account roles used to label instruction accounts
+const (
+	RoleGridAccount     = "grid_account"
+	RoleProducerAccount = "producer_account"
+	RoleConsumerAccount = "consumer_account"
+	RoleMintRecord      = "mint_record"
+	RoleListingAccount  = "listing_account"
+	RoleGrid            = "grid"
+	RoleProducer        = "producer"
+	RoleConsumer        = "consumer"
+	RoleBuyer           = "buyer"
+	RoleAuthority       = "authority"
+	RoleGridAuthority   = "grid_authority"
+	RoleSystemProgram   = "system_program"
+)
+
+// instructionLayout describes how an instruction is identified and decoded
+type instructionLayout struct {
+	name          string
+	discriminator [8]byte
+	accounts      []string
+	newArgs       func() interface{}
+}
+
+// instructionLayouts mirrors the account orderings used by the builders in instructions.go
+var instructionLayouts = []instructionLayout{
+	{
+		name:          InstructionInitializeGrid,
+		discriminator: InitializeGridDiscriminator,
+		accounts:      []string{RoleGridAccount, RoleGrid, RoleAuthority, RoleSystemProgram},
+	},
+	{
+		name:          InstructionInitializeProducer,
+		discriminator: InitializeProducerDiscriminator,
+		accounts:      []string{RoleProducerAccount, RoleProducer, RoleAuthority, RoleSystemProgram},
+	},
+	{
+		name:          InstructionInitializeConsumer,
+		discriminator: InitializeConsumerDiscriminator,
+		accounts:      []string{RoleConsumerAccount, RoleConsumer, RoleAuthority, RoleSystemProgram},
+	},
+	{
+		name:          InstructionMintEnergyTokens,
+		discriminator: MintEnergyTokensDiscriminator,
+		accounts:      []string{RoleProducerAccount, RoleGridAccount, RoleMintRecord, RoleProducer, RoleGridAuthority, RoleSystemProgram},
+		newArgs:       func() interface{} { return &MintEnergyTokensArgs{} },
+	},
+	{
+		name:          InstructionListTokensForSale,
+		discriminator: ListTokensForSaleDiscriminator,
+		accounts:      []string{RoleProducerAccount, RoleListingAccount, RoleProducer, RoleSystemProgram},
+		newArgs:       func() interface{} { return &ListTokensForSaleArgs{} },
+	},
+	{
+		name:          InstructionCancelListing,
+		discriminator: CancelListingDiscriminator,
+		accounts:      []string{RoleListingAccount, RoleProducerAccount, RoleProducer},
+	},
+	{
+		name:          InstructionBuyTokens,
+		discriminator: BuyTokensDiscriminator,
+		accounts:      []string{RoleListingAccount, RoleConsumerAccount, RoleProducer, RoleBuyer, RoleSystemProgram},
+		newArgs:       func() interface{} { return &BuyTokensArgs{} },
+	},
+	{
+		name:          InstructionMintConsumptionTokens,
+		discriminator: MintConsumptionTokensDiscriminator,
+		accounts:      []string{RoleConsumerAccount, RoleGridAccount, RoleGridAuthority},
+		newArgs:       func() interface{} { return &MintConsumptionTokensArgs{} },
+	},
+}
+
+// DecodedAccount is an instruction account labelled with its role
+type DecodedAccount struct {
+	Role       string           `json:"role"`
+	PublicKey  solana.PublicKey `json:"public_key"`
+	IsSigner   bool             `json:"is_signer"`
+	IsWritable bool             `json:"is_writable"`
+}
+
+// DecodedInstruction is a Zonne program instruction decoded from its raw form.
+// Args holds one of the *Args types from instructions.go, or nil for
+// instructions without arguments.
+type DecodedInstruction struct {
+	Name          string           `json:"name"`
+	Discriminator [8]byte          `json:"discriminator"`
+	Args          interface{}      `json:"args,omitempty"`
+	Accounts      []DecodedAccount `json:"accounts"`
+}
+
+// Account returns the account with the given role
+func (d *DecodedInstruction) Account(role string) (solana.PublicKey, bool) {
+	for _, account := range d.Accounts {
+		if account.Role == role {
+			return account.PublicKey, true
+		}
+	}
+	return solana.PublicKey{}, false
+}
+
+// String renders the decoded instruction in a human-readable form
+func (d *DecodedInstruction) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Instruction: %s\n", d.Name)
+
+	switch args := d.Args.(type) {
+	case *MintEnergyTokensArgs:
+		fmt.Fprintf(&b, "  Amount:      %d\n", args.Amount)
+		fmt.Fprintf(&b, "  Energy Type: %s\n", args.EnergyType)
+	case *ListTokensForSaleArgs:
+		fmt.Fprintf(&b, "  Amount:      %d\n", args.Amount)
+		fmt.Fprintf(&b, "  Price:       %d lamports\n", args.PriceLamports)
+		fmt.Fprintf(&b, "  Energy Type: %s\n", args.EnergyType)
+	case *BuyTokensArgs:
+		fmt.Fprintf(&b, "  Listing ID:  %s\n", args.ListingID)
+	case *MintConsumptionTokensArgs:
+		fmt.Fprintf(&b, "  Amount:      %d\n", args.Amount)
+	}
+
+	b.WriteString("  Accounts:\n")
+	for i, account := range d.Accounts {
+		flags := ""
+		if account.IsWritable {
+			flags += "w"
+		}
+		if account.IsSigner {
+			flags += "s"
+		}
+		fmt.Fprintf(&b, "    #%d %-16s %s", i, account.Role, account.PublicKey)
+		if flags != "" {
+			fmt.Fprintf(&b, " [%s]", flags)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// DecodeInstructionData decodes an instruction of the upstream program from its accounts and data
+func DecodeInstructionData(accounts []*solana.AccountMeta, data []byte) (*DecodedInstruction, error) {
+	return decodeInstructionData(accounts, data, defaultDiscriminators())
+}
+
+// decodeInstructionData decodes an instruction, identifying it with the given discriminators
+func decodeInstructionData(accounts []*solana.AccountMeta, data []byte, discriminators map[string][8]byte) (*DecodedInstruction, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("instruction data too short: %d bytes", len(data))
+	}
+
+	var discriminator [8]byte
+	copy(discriminator[:], data[:8])
+
+	var layout *instructionLayout
+	for name, d := range discriminators {
+		if d == discriminator {
+			layout = findInstructionLayout(name)
+			break
+		}
+	}
+	if layout == nil {
+		return nil, fmt.Errorf("unknown instruction discriminator: %v", discriminator)
+	}
+
+	if len(accounts) < len(layout.accounts) {
+		return nil, fmt.Errorf("%s expects %d accounts, got %d", layout.name, len(layout.accounts), len(accounts))
+	}
+
+	decoded := &DecodedInstruction{
+		Name:          layout.name,
+		Discriminator: discriminator,
+	}
+
+	if layout.newArgs != nil {
+		args := layout.newArgs()
+		if err := borsh.Deserialize(args, data[8:]); err != nil {
+			return nil, fmt.Errorf("failed to deserialize %s arguments: %w", layout.name, err)
+		}
+		decoded.Args = args
+	}
+
+	for i, account := range accounts {
+		role := "remaining"
+		if i < len(layout.accounts) {
+			role = layout.accounts[i]
+		}
+		decoded.Accounts = append(decoded.Accounts, DecodedAccount{
+			Role:       role,
+			PublicKey:  account.PublicKey,
+			IsSigner:   account.IsSigner,
+			IsWritable: account.IsWritable,
+		})
+	}
+
+	return decoded, nil
+}
+
+// DecodeInstruction decodes an instruction targeting the client's program
+func (c *Client) DecodeInstruction(instruction solana.Instruction) (*DecodedInstruction, error) {
+	if !instruction.ProgramID().Equals(c.programID) {
+		return nil, fmt.Errorf("instruction targets program %s, not %s", instruction.ProgramID(), c.programID)
+	}
+
+	data, err := instruction.Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instruction data: %w", err)
+	}
+
+	return decodeInstructionData(instruction.Accounts(), data, c.discriminators)
+}
+
+// DecodeCompiledInstruction decodes a compiled instruction of a transaction message
+func (c *Client) DecodeCompiledInstruction(message *solana.Message, instruction solana.CompiledInstruction) (*DecodedInstruction, error) {
+	programID, err := message.Program(instruction.ProgramIDIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve program ID: %w", err)
+	}
+	if !programID.Equals(c.programID) {
+		return nil, fmt.Errorf("instruction targets program %s, not %s", programID, c.programID)
+	}
+
+	accounts, err := instruction.ResolveInstructionAccounts(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve instruction accounts: %w", err)
+	}
+
+	return decodeInstructionData(accounts, instruction.Data, c.discriminators)
+}
+
+// DecodeTransaction decodes every instruction of the transaction that targets
+// the client's program, skipping instructions for other programs
+func (c *Client) DecodeTransaction(transaction *solana.Transaction) ([]*DecodedInstruction, error) {
+	var decoded []*DecodedInstruction
+	for i, instruction := range transaction.Message.Instructions {
+		programID, err := transaction.Message.Program(instruction.ProgramIDIndex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve program ID of instruction %d: %w", i, err)
+		}
+		if !programID.Equals(c.programID) {
+			continue
+		}
+
+		instr, err := c.DecodeCompiledInstruction(&transaction.Message, instruction)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode instruction %d: %w", i, err)
+		}
+		decoded = append(decoded, instr)
+	}
+
+	return decoded, nil
+}
+
+// GetDecodedTransaction fetches a confirmed transaction and decodes its Zonne instructions
+func (c *Client) GetDecodedTransaction(ctx context.Context, signature solana.Signature) ([]*DecodedInstruction, error) {
+	maxVersion := uint64(0)
+	result, err := c.rpcClient.GetTransaction(ctx, signature, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     rpc.CommitmentConfirmed,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if result.Transaction == nil {
+		return nil, fmt.Errorf("transaction not found")
+	}
+
+	transaction, err := decodeTransactionResult(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+
+	return c.DecodeTransaction(transaction)
+}
+
+// decodeTransactionResult decodes a fetched transaction, resolving the address
+// table lookups of a v0 message from the loaded addresses in its meta
+func decodeTransactionResult(result *rpc.GetTransactionResult) (*solana.Transaction, error) {
+	transaction, err := result.Transaction.GetTransaction()
+	if err != nil {
+		return nil, err
+	}
+	lookups := transaction.Message.GetAddressTableLookups()
+	if len(lookups) == 0 {
+		return transaction, nil
+	}
+	if result.Meta == nil {
+		return nil, fmt.Errorf("transaction uses address lookup tables but has no meta")
+	}
+	if err := resolveLoadedAddresses(&transaction.Message, result.Meta.LoadedAddresses); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// resolveLoadedAddresses appends the addresses the message loaded from lookup
+// tables to its account keys. The RPC lists them writable first, then
+// read-only, each in lookup order, which is the order the runtime indexes them.
+func resolveLoadedAddresses(message *solana.Message, loaded rpc.LoadedAddresses) error {
+	lookups := message.GetAddressTableLookups()
+	writable, readonly := loaded.Writable, loaded.ReadOnly
+	tables := make(map[solana.PublicKey]solana.PublicKeySlice, len(lookups))
+	for _, lookup := range lookups {
+		if len(writable) < len(lookup.WritableIndexes) || len(readonly) < len(lookup.ReadonlyIndexes) {
+			return fmt.Errorf("loaded addresses do not cover the lookups of table %s", lookup.AccountKey)
+		}
+		table := tables[lookup.AccountKey]
+		for i, index := range lookup.WritableIndexes {
+			table = setTableEntry(table, index, writable[i])
+		}
+		for i, index := range lookup.ReadonlyIndexes {
+			table = setTableEntry(table, index, readonly[i])
+		}
+		tables[lookup.AccountKey] = table
+		writable = writable[len(lookup.WritableIndexes):]
+		readonly = readonly[len(lookup.ReadonlyIndexes):]
+	}
+	if len(writable) != 0 || len(readonly) != 0 {
+		return fmt.Errorf("%d loaded addresses are not referenced by the lookups", len(writable)+len(readonly))
+	}
+
+	if err := message.SetAddressTables(tables); err != nil {
+		return err
+	}
+	return message.ResolveLookups()
+}
+
+// setTableEntry sets the entry of a partial lookup table, growing it as needed
+func setTableEntry(table solana.PublicKeySlice, index uint8, address solana.PublicKey) solana.PublicKeySlice {
+	for len(table) <= int(index) {
+		table = append(table, solana.PublicKey{})
+	}
+	table[index] = address
+	return table
+}
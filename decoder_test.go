@@ -0,0 +1,91 @@
+package zonnegosdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestDecodeVersionedTransaction(t *testing.T) {
+	c := NewClientWithCustomProgram("", solana.SystemProgramID)
+	c.programID = solana.NewWallet().PublicKey()
+
+	params := MintRecordCreationParams{
+		Producer:      solana.NewWallet().PublicKey(),
+		Grid:          solana.NewWallet().PublicKey(),
+		GridAuthority: solana.NewWallet().PublicKey(),
+		Amount:        uint64(5 * KilowattHour),
+		EnergyType:    EnergyTypeSolar,
+	}
+	instruction, err := c.MintEnergyTokens(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load the grid account, the producer and the system program from a table
+	accounts := instruction.Accounts()
+	table := solana.NewWallet().PublicKey()
+	tableAddresses := solana.PublicKeySlice{solana.NewWallet().PublicKey(), accounts[5].PublicKey, accounts[1].PublicKey, accounts[3].PublicKey}
+	transaction, err := solana.NewTransaction([]solana.Instruction{instruction}, solana.Hash{},
+		solana.TransactionPayer(params.GridAuthority),
+		solana.TransactionAddressTables(map[solana.PublicKey]solana.PublicKeySlice{table: tableAddresses}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := transaction.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookups := transaction.Message.GetAddressTableLookups()
+	if len(lookups) != 1 {
+		t.Fatalf("transaction has %d address table lookups, want 1", len(lookups))
+	}
+	var loaded rpc.LoadedAddresses
+	for _, index := range lookups[0].WritableIndexes {
+		loaded.Writable = append(loaded.Writable, tableAddresses[index])
+	}
+	for _, index := range lookups[0].ReadonlyIndexes {
+		loaded.ReadOnly = append(loaded.ReadOnly, tableAddresses[index])
+	}
+
+	var result rpc.GetTransactionResult
+	response := fmt.Sprintf(`{"slot": 1, "transaction": [%q, "base64"], "meta": {"loadedAddresses": {"writable": [], "readonly": []}}}`, base64.StdEncoding.EncodeToString(data))
+	if err := json.Unmarshal([]byte(response), &result); err != nil {
+		t.Fatal(err)
+	}
+	result.Meta.LoadedAddresses = loaded
+
+	decoded, err := decodeTransactionResult(&result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	instructions, err := c.DecodeTransaction(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instructions) != 1 {
+		t.Fatalf("decoded %d instructions, want 1", len(instructions))
+	}
+	args, ok := instructions[0].Args.(*MintEnergyTokensArgs)
+	if instructions[0].Name != InstructionMintEnergyTokens || !ok {
+		t.Fatalf("decoded %s, want %s", instructions[0].Name, InstructionMintEnergyTokens)
+	}
+	if args.Amount != params.Amount || args.EnergyType != params.EnergyType {
+		t.Errorf("decoded args %+v, want amount %d and energy type %v", *args, params.Amount, params.EnergyType)
+	}
+	for i, account := range instructions[0].Accounts {
+		if !account.PublicKey.Equals(accounts[i].PublicKey) {
+			t.Errorf("account %d is %s, want %s", i, account.PublicKey, accounts[i].PublicKey)
+		}
+	}
+
+	result.Meta.LoadedAddresses = rpc.LoadedAddresses{}
+	if _, err := decodeTransactionResult(&result); err == nil {
+		t.Error("decoded a v0 transaction without its loaded addresses")
+	}
+}
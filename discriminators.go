@@ -0,0 +1,92 @@
+package zonnegosdk
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// Anchor discriminator namespaces
+const (
+	instructionNamespace = "global"
+	accountNamespace     = "account"
+	eventNamespace       = "event"
+)
+
+// ComputeInstructionDiscriminator computes the Anchor discriminator of an
+// instruction from its snake_case name: sha256("global:<name>")[:8]
+func ComputeInstructionDiscriminator(name string) [8]byte {
+	return anchorDiscriminator(instructionNamespace, name)
+}
+
+// ComputeAccountDiscriminator computes the Anchor discriminator of an
+// account type from its PascalCase name: sha256("account:<Name>")[:8]
+func ComputeAccountDiscriminator(name string) [8]byte {
+	return anchorDiscriminator(accountNamespace, name)
+}
+
+// ComputeEventDiscriminator computes the Anchor discriminator of an event
+// from its PascalCase name: sha256("event:<Name>")[:8]
+func ComputeEventDiscriminator(name string) [8]byte {
+	return anchorDiscriminator(eventNamespace, name)
+}
+
+// anchorDiscriminator computes sha256("<namespace>:<name>")[:8]
+func anchorDiscriminator(namespace, name string) [8]byte {
+	sum := sha256.Sum256([]byte(namespace + ":" + name))
+	var d [8]byte
+	copy(d[:], sum[:8])
+	return d
+}
+
+// CheckDiscriminators recomputes every hardcoded instruction discriminator
+// from its instruction name and reports the first that does not match
+func CheckDiscriminators() error {
+	for _, layout := range instructionLayouts {
+		if computed := ComputeInstructionDiscriminator(layout.name); computed != layout.discriminator {
+			return fmt.Errorf("discriminator of %s is %v, computed %v", layout.name, layout.discriminator, computed)
+		}
+	}
+	return nil
+}
+
+// WithInstructionNames configures the on-chain names of instructions for
+// forks of the program that renamed them. Keys are the SDK instruction names
+// (InstructionMintEnergyTokens, ...) and values the fork's snake_case names;
+// their discriminators are computed from the fork's names. A key that is not
+// an SDK instruction name makes client creation fail.
+func WithInstructionNames(names map[string]string) ClientOption {
+	return func(c *Client) {
+		for instruction, name := range names {
+			if _, ok := c.discriminators[instruction]; !ok {
+				if c.optionErr == nil {
+					c.optionErr = fmt.Errorf("unknown instruction %q in instruction names", instruction)
+				}
+				continue
+			}
+			c.discriminators[instruction] = ComputeInstructionDiscriminator(name)
+		}
+	}
+}
+
+// defaultDiscriminators returns the discriminators of the upstream program
+func defaultDiscriminators() map[string][8]byte {
+	discriminators := make(map[string][8]byte, len(instructionLayouts))
+	for _, layout := range instructionLayouts {
+		discriminators[layout.name] = layout.discriminator
+	}
+	return discriminators
+}
+
+// InstructionDiscriminator returns the discriminator the client uses for an instruction
+func (c *Client) InstructionDiscriminator(instruction string) [8]byte {
+	return c.discriminators[instruction]
+}
+
+// instructionData returns a fresh slice holding the instruction's
+// discriminator followed by its serialized arguments
+func (c *Client) instructionData(instruction string, args []byte) []byte {
+	discriminator := c.InstructionDiscriminator(instruction)
+	data := make([]byte, 0, len(discriminator)+len(args))
+	data = append(data, discriminator[:]...)
+	return append(data, args...)
+}
@@ -0,0 +1,134 @@
+package zonnegosdk
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/akbariandev/zonnegosdk/idl"
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestCheckDiscriminators(t *testing.T) {
+	if err := CheckDiscriminators(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestComputeDiscriminatorsMatchGenerated(t *testing.T) {
+	instructions := map[string][8]byte{
+		InstructionInitializeGrid:        idl.InitializeGridDiscriminator,
+		InstructionInitializeProducer:    idl.InitializeProducerDiscriminator,
+		InstructionInitializeConsumer:    idl.InitializeConsumerDiscriminator,
+		InstructionMintEnergyTokens:      idl.MintEnergyTokensDiscriminator,
+		InstructionListTokensForSale:     idl.ListTokensForSaleDiscriminator,
+		InstructionCancelListing:         idl.CancelListingDiscriminator,
+		InstructionBuyTokens:             idl.BuyTokensDiscriminator,
+		InstructionMintConsumptionTokens: idl.MintConsumptionTokensDiscriminator,
+	}
+	for name, want := range instructions {
+		if got := ComputeInstructionDiscriminator(name); got != want {
+			t.Errorf("instruction %s: computed %v, generated %v", name, got, want)
+		}
+	}
+
+	accounts := map[string][8]byte{
+		"GridAccount":     idl.GridAccountDiscriminator,
+		"ProducerAccount": idl.ProducerAccountDiscriminator,
+		"ConsumerAccount": idl.ConsumerAccountDiscriminator,
+		"MintRecord":      idl.MintRecordDiscriminator,
+		"ListingAccount":  idl.ListingAccountDiscriminator,
+	}
+	for name, want := range accounts {
+		if got := ComputeAccountDiscriminator(name); got != want {
+			t.Errorf("account %s: computed %v, generated %v", name, got, want)
+		}
+	}
+
+	events := map[string][8]byte{
+		"GridInitialized":     idl.GridInitializedEventDiscriminator,
+		"ProducerInitialized": idl.ProducerInitializedEventDiscriminator,
+		"ConsumerInitialized": idl.ConsumerInitializedEventDiscriminator,
+		"TokensMinted":        idl.TokensMintedEventDiscriminator,
+		"TokensListed":        idl.TokensListedEventDiscriminator,
+		"ListingCancelled":    idl.ListingCancelledEventDiscriminator,
+		"TokensPurchased":     idl.TokensPurchasedEventDiscriminator,
+		"ConsumptionMinted":   idl.ConsumptionMintedEventDiscriminator,
+	}
+	for name, want := range events {
+		if got := ComputeEventDiscriminator(name); got != want {
+			t.Errorf("event %s: computed %v, generated %v", name, got, want)
+		}
+	}
+}
+
+func TestWithInstructionNames(t *testing.T) {
+	c := NewClientWithCustomProgram("", solana.SystemProgramID, WithInstructionNames(map[string]string{
+		InstructionBuyTokens: "purchase_energy",
+	}))
+
+	data := c.instructionData(InstructionBuyTokens, []byte{1, 2})
+	want := ComputeInstructionDiscriminator("purchase_energy")
+	if !bytes.Equal(data[:8], want[:]) {
+		t.Errorf("discriminator is %v, want %v", data[:8], want)
+	}
+	if !bytes.Equal(data[8:], []byte{1, 2}) {
+		t.Errorf("arguments are %v, want [1 2]", data[8:])
+	}
+
+	upstream := NewClientWithCustomProgram("", solana.SystemProgramID)
+	if data := upstream.instructionData(InstructionBuyTokens, nil); !bytes.Equal(data, idl.BuyTokensDiscriminator[:]) {
+		t.Errorf("upstream discriminator is %v, want %v", data, idl.BuyTokensDiscriminator)
+	}
+	if data := c.instructionData(InstructionMintEnergyTokens, nil); !bytes.Equal(data, idl.MintEnergyTokensDiscriminator[:]) {
+		t.Errorf("unrenamed discriminator is %v, want %v", data, idl.MintEnergyTokensDiscriminator)
+	}
+}
+
+func TestWithInstructionNamesRejectsUnknownInstruction(t *testing.T) {
+	_, err := NewClientChecked("http://localhost:8899", solana.SystemProgramID.String(), WithInstructionNames(map[string]string{
+		"BuyTokens": "purchase_energy",
+	}))
+	if err == nil {
+		t.Fatal("expected an error for an instruction name that is not an SDK instruction")
+	}
+}
+
+func TestCompareIDLFork(t *testing.T) {
+	data, err := os.ReadFile("idl/zonne.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream, err := ParseIDL(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mismatches := CompareIDL(upstream); len(mismatches) != 0 {
+		t.Fatalf("upstream IDL mismatches: %v", mismatches)
+	}
+
+	fork, err := ParseIDL(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	discriminator := ComputeInstructionDiscriminator("purchase_energy")
+	for i := range fork.Instructions {
+		if fork.Instructions[i].Name == InstructionBuyTokens {
+			fork.Instructions[i].Name = "purchase_energy"
+			fork.Instructions[i].Discriminator = make([]int, len(discriminator))
+			for j, b := range discriminator {
+				fork.Instructions[i].Discriminator[j] = int(b)
+			}
+		}
+	}
+
+	c := NewClientWithCustomProgram("", solana.SystemProgramID, WithInstructionNames(map[string]string{
+		InstructionBuyTokens: "purchase_energy",
+	}))
+	if mismatches := compareIDL(fork, c.discriminators); len(mismatches) != 0 {
+		t.Errorf("fork configured with its instruction names mismatches: %v", mismatches)
+	}
+	if mismatches := CompareIDL(fork); len(mismatches) == 0 {
+		t.Error("fork compared against the upstream discriminators has no mismatches")
+	}
+}
@@ -0,0 +1,89 @@
+package zonnegosdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// energyTypes lists every valid energy type, in on-chain order
+var energyTypes = []EnergyType{EnergyTypeSolar, EnergyTypeWind, EnergyTypeHydro, EnergyTypeOther}
+
+// EnergyTypes returns every valid energy type
+func EnergyTypes() []EnergyType {
+	return append([]EnergyType(nil), energyTypes...)
+}
+
+// ParseEnergyTypeStrict parses an energy type name such as "solar" or
+// "Wind", ignoring case. It fails on unknown names and on out-of-range values.
+func ParseEnergyTypeStrict(s string) (EnergyType, error) {
+	name := strings.TrimSpace(s)
+	for _, energyType := range energyTypes {
+		if strings.EqualFold(name, energyType.String()) {
+			return energyType, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid energy type %q: must be one of %s", s, energyTypeNames())
+}
+
+// IsValid reports whether the energy type is one defined by the program
+func (e EnergyType) IsValid() bool {
+	return IsValidEnergyType(e)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (e EnergyType) MarshalText() ([]byte, error) {
+	if !e.IsValid() {
+		return nil, fmt.Errorf("invalid energy type %d", uint8(e))
+	}
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (e *EnergyType) UnmarshalText(text []byte) error {
+	energyType, err := ParseEnergyTypeStrict(string(text))
+	if err != nil {
+		return err
+	}
+	*e = energyType
+	return nil
+}
+
+// MarshalJSON encodes the energy type as its name
+func (e EnergyType) MarshalJSON() ([]byte, error) {
+	text, err := e.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes an energy type from its name or its numeric value
+func (e *EnergyType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return e.UnmarshalText([]byte(name))
+	}
+
+	value, err := strconv.ParseUint(string(data), 10, 8)
+	if err != nil || !EnergyType(value).IsValid() {
+		return fmt.Errorf("invalid energy type %s", data)
+	}
+	*e = EnergyType(value)
+	return nil
+}
+
+// Set implements flag.Value
+func (e *EnergyType) Set(s string) error {
+	return e.UnmarshalText([]byte(s))
+}
+
+// energyTypeNames lists the valid energy type names for error messages
+func energyTypeNames() string {
+	names := make([]string, len(energyTypes))
+	for i, energyType := range energyTypes {
+		names[i] = energyType.String()
+	}
+	return strings.Join(names, ", ")
+}
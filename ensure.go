@@ -0,0 +1,336 @@
+package zonnegosdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// MaxTransactionSize is the largest serialized transaction the cluster
+// accepts, in bytes
+const MaxTransactionSize = 1232
+
+// maxMultipleAccounts is the most accounts getMultipleAccounts returns per call
+const maxMultipleAccounts = 100
+
+// EnsureStatus is the state of an account after an ensure operation
+type EnsureStatus string
+
+const (
+	// EnsureExisting means the account was already initialized
+	EnsureExisting EnsureStatus = "existing"
+	// EnsureCreated means the account was initialized by the operation
+	EnsureCreated EnsureStatus = "created"
+	// EnsureMissing means the account is not initialized: the plan has not
+	// been executed, or the transaction that would create it failed
+	EnsureMissing EnsureStatus = "missing"
+)
+
+// EnsureResult reports the state of one grid, producer or consumer account
+type EnsureResult struct {
+	// Key is the grid, producer or consumer wallet
+	Key solana.PublicKey `json:"key"`
+	// Address is the account PDA
+	Address solana.PublicKey `json:"address"`
+	Status  EnsureStatus     `json:"status"`
+	// Signature is the transaction that created the account
+	Signature *solana.Signature `json:"signature,omitempty"`
+}
+
+// EnsureBatch is one transaction of initialization instructions
+type EnsureBatch struct {
+	Keys         []solana.PublicKey
+	Instructions []solana.Instruction
+}
+
+// EnsurePlan lists which accounts exist and batches the initialization of
+// the missing ones into as few transactions as fit MaxTransactionSize
+type EnsurePlan struct {
+	// Authority signs and pays for every batch
+	Authority solana.PublicKey
+	Results   []EnsureResult
+	Batches   []EnsureBatch
+}
+
+// Created returns the results of the accounts created by the plan
+func (p *EnsurePlan) Created() []EnsureResult {
+	return p.filter(EnsureCreated)
+}
+
+// Missing returns the results of the accounts not initialized yet
+func (p *EnsurePlan) Missing() []EnsureResult {
+	return p.filter(EnsureMissing)
+}
+
+func (p *EnsurePlan) filter(status EnsureStatus) []EnsureResult {
+	var results []EnsureResult
+	for _, result := range p.Results {
+		if result.Status == status {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// ensureKind describes how to derive and initialize one account type
+type ensureKind struct {
+	derive     func(key solana.PublicKey) (solana.PublicKey, uint8, error)
+	initialize func(key, authority solana.PublicKey) (solana.Instruction, error)
+}
+
+func (c *Client) gridKind() ensureKind {
+	return ensureKind{
+		derive: c.DeriveGridAccountPDA,
+		initialize: func(key, authority solana.PublicKey) (solana.Instruction, error) {
+			return c.InitializeGrid(GridAccountCreationParams{Grid: key, Authority: authority})
+		},
+	}
+}
+
+func (c *Client) producerKind() ensureKind {
+	return ensureKind{
+		derive: c.DeriveProducerAccountPDA,
+		initialize: func(key, authority solana.PublicKey) (solana.Instruction, error) {
+			return c.InitializeProducer(ProducerAccountCreationParams{Producer: key, Authority: authority})
+		},
+	}
+}
+
+func (c *Client) consumerKind() ensureKind {
+	return ensureKind{
+		derive: c.DeriveConsumerAccountPDA,
+		initialize: func(key, authority solana.PublicKey) (solana.Instruction, error) {
+			return c.InitializeConsumer(ConsumerAccountCreationParams{Consumer: key, Authority: authority})
+		},
+	}
+}
+
+// PlanEnsureGrid checks which grid accounts exist and plans the
+// initialization of the others
+func (c *Client) PlanEnsureGrid(ctx context.Context, authority solana.PublicKey, grids ...solana.PublicKey) (*EnsurePlan, error) {
+	return c.planEnsure(ctx, c.gridKind(), authority, grids)
+}
+
+// PlanEnsureProducer checks which producer accounts exist and plans the
+// initialization of the others
+func (c *Client) PlanEnsureProducer(ctx context.Context, authority solana.PublicKey, producers ...solana.PublicKey) (*EnsurePlan, error) {
+	return c.planEnsure(ctx, c.producerKind(), authority, producers)
+}
+
+// PlanEnsureConsumer checks which consumer accounts exist and plans the
+// initialization of the others
+func (c *Client) PlanEnsureConsumer(ctx context.Context, authority solana.PublicKey, consumers ...solana.PublicKey) (*EnsurePlan, error) {
+	return c.planEnsure(ctx, c.consumerKind(), authority, consumers)
+}
+
+// EnsureGrid initializes the grid accounts that do not exist yet and reports
+// created versus existing per grid. It is safe to call repeatedly.
+func (c *Client) EnsureGrid(ctx context.Context, authority solana.PrivateKey, grids ...solana.PublicKey) ([]EnsureResult, error) {
+	return c.ensure(ctx, c.gridKind(), authority, grids)
+}
+
+// EnsureProducer initializes the producer accounts that do not exist yet and
+// reports created versus existing per producer. It is safe to call repeatedly.
+func (c *Client) EnsureProducer(ctx context.Context, authority solana.PrivateKey, producers ...solana.PublicKey) ([]EnsureResult, error) {
+	return c.ensure(ctx, c.producerKind(), authority, producers)
+}
+
+// EnsureConsumer initializes the consumer accounts that do not exist yet and
+// reports created versus existing per consumer. It is safe to call repeatedly.
+func (c *Client) EnsureConsumer(ctx context.Context, authority solana.PrivateKey, consumers ...solana.PublicKey) ([]EnsureResult, error) {
+	return c.ensure(ctx, c.consumerKind(), authority, consumers)
+}
+
+func (c *Client) ensure(ctx context.Context, kind ensureKind, authority solana.PrivateKey, keys []solana.PublicKey) ([]EnsureResult, error) {
+	v := newValidator("ensure")
+	v.privateKey("Authority", authority)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	plan, err := c.planEnsure(ctx, kind, authority.PublicKey(), keys)
+	if err != nil {
+		return nil, err
+	}
+	err = c.ExecuteEnsurePlan(ctx, plan, authority)
+	return plan.Results, err
+}
+
+// planEnsure fetches the accounts of the keys and batches the initialization
+// of the missing ones. Duplicate keys are reported once.
+func (c *Client) planEnsure(ctx context.Context, kind ensureKind, authority solana.PublicKey, keys []solana.PublicKey) (*EnsurePlan, error) {
+	plan := &EnsurePlan{Authority: authority}
+
+	seen := make(map[solana.PublicKey]bool)
+	var addresses []solana.PublicKey
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		address, _, err := kind.derive(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive account PDA of %s: %w", key, err)
+		}
+		plan.Results = append(plan.Results, EnsureResult{Key: key, Address: address})
+		addresses = append(addresses, address)
+	}
+
+	exists, err := c.accountsExist(ctx, addresses, c.commitment)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []solana.PublicKey
+	var instructions []solana.Instruction
+	for i := range plan.Results {
+		if exists[i] {
+			plan.Results[i].Status = EnsureExisting
+			continue
+		}
+		plan.Results[i].Status = EnsureMissing
+
+		instruction, err := kind.initialize(plan.Results[i].Key, authority)
+		if err != nil {
+			return nil, err
+		}
+		missing = append(missing, plan.Results[i].Key)
+		instructions = append(instructions, instruction)
+	}
+
+	batches, err := BatchInstructions(authority, instructions)
+	if err != nil {
+		return nil, err
+	}
+	start := 0
+	for _, batch := range batches {
+		plan.Batches = append(plan.Batches, EnsureBatch{Keys: missing[start : start+len(batch)], Instructions: batch})
+		start += len(batch)
+	}
+
+	return plan, nil
+}
+
+// ExecuteEnsurePlan sends the plan's batches in order, signed by the
+// authority. After each confirmed batch it fetches the batch's accounts at
+// finalized commitment and marks those that exist as created. It stops at the
+// first batch that fails or leaves an account missing; running a new plan
+// later picks up the accounts still missing.
+func (c *Client) ExecuteEnsurePlan(ctx context.Context, plan *EnsurePlan, authority solana.PrivateKey) error {
+	if len(plan.Batches) > 0 && !authority.PublicKey().Equals(plan.Authority) {
+		return fmt.Errorf("plan authority is %s, not %s", plan.Authority, authority.PublicKey())
+	}
+
+	index := make(map[solana.PublicKey]int, len(plan.Results))
+	for i, result := range plan.Results {
+		index[result.Key] = i
+	}
+
+	for i, batch := range plan.Batches {
+		signature, err := c.sendInstructions(ctx, authority.PublicKey(), []solana.PrivateKey{authority}, batch.Instructions)
+		if err != nil {
+			return fmt.Errorf("failed to send batch %d of %d: %w", i+1, len(plan.Batches), err)
+		}
+
+		addresses := make([]solana.PublicKey, len(batch.Keys))
+		for j, key := range batch.Keys {
+			addresses[j] = plan.Results[index[key]].Address
+		}
+		exists, err := c.accountsExist(ctx, addresses, rpc.CommitmentFinalized)
+		if err != nil {
+			return fmt.Errorf("failed to check the accounts of batch %d of %d: %w", i+1, len(plan.Batches), err)
+		}
+
+		missing := 0
+		for j, key := range batch.Keys {
+			if !exists[j] {
+				missing++
+				continue
+			}
+			result := &plan.Results[index[key]]
+			result.Status = EnsureCreated
+			result.Signature = &signature
+		}
+		if missing > 0 {
+			return fmt.Errorf("batch %d of %d (%s) left %d of %d accounts missing", i+1, len(plan.Batches), signature, missing, len(batch.Keys))
+		}
+	}
+
+	return nil
+}
+
+// BatchInstructions splits instructions, in order, into as few transactions
+// paid for by payer as fit MaxTransactionSize
+func BatchInstructions(payer solana.PublicKey, instructions []solana.Instruction) ([][]solana.Instruction, error) {
+	var batches [][]solana.Instruction
+	var current []solana.Instruction
+
+	for _, instruction := range instructions {
+		candidate := append(current[:len(current):len(current)], instruction)
+		size, err := transactionSize(payer, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if size <= MaxTransactionSize {
+			current = candidate
+			continue
+		}
+
+		if len(current) == 0 {
+			return nil, fmt.Errorf("instruction does not fit in a transaction: %d bytes", size)
+		}
+		batches = append(batches, current)
+		current = []solana.Instruction{instruction}
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+
+	return batches, nil
+}
+
+// transactionSize returns the serialized size of a signed transaction with
+// the instructions
+func transactionSize(payer solana.PublicKey, instructions []solana.Instruction) (int, error) {
+	transaction, err := solana.NewTransaction(instructions, solana.Hash{}, solana.TransactionPayer(payer))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	transaction.Signatures = make([]solana.Signature, transaction.Message.Header.NumRequiredSignatures)
+
+	data, err := transaction.MarshalBinary()
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize transaction: %w", err)
+	}
+	return len(data), nil
+}
+
+// accountsExist reports for each address whether an account exists there at
+// the given commitment
+func (c *Client) accountsExist(ctx context.Context, addresses []solana.PublicKey, commitment rpc.CommitmentType) ([]bool, error) {
+	exists := make([]bool, 0, len(addresses))
+	for start := 0; start < len(addresses); start += maxMultipleAccounts {
+		end := start + maxMultipleAccounts
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+
+		result, err := c.rpcClient.GetMultipleAccountsWithOpts(ctx, addresses[start:end], &rpc.GetMultipleAccountsOpts{
+			Encoding:   solana.EncodingBase64,
+			Commitment: commitment,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get accounts: %w", err)
+		}
+		if len(result.Value) != end-start {
+			return nil, fmt.Errorf("failed to get accounts: expected %d results, got %d", end-start, len(result.Value))
+		}
+		for _, account := range result.Value {
+			exists = append(exists, account != nil)
+		}
+	}
+	return exists, nil
+}
@@ -0,0 +1,124 @@
+package zonnegosdk
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/near/borsh-go"
+)
+
+// Event names as emitted by the Zonne program
+const (
+	EventGridInitialized     = "GridInitialized"
+	EventProducerInitialized = "ProducerInitialized"
+	EventConsumerInitialized = "ConsumerInitialized"
+	EventTokensMinted        = "TokensMinted"
+	EventTokensListed        = "TokensListed"
+	EventListingCancelled    = "ListingCancelled"
+	EventTokensPurchased     = "TokensPurchased"
+	EventConsumptionMinted   = "ConsumptionMinted"
+)
+
+// Log prefixes written by the runtime and by Anchor's emit!
+const (
+	programDataLogPrefix = "Program data: "
+	programLogPrefix     = "Program "
+)
+
+// ErrUnknownEvent is returned when event data does not match any event known to the SDK
+var ErrUnknownEvent = errors.New("unknown event discriminator")
+
+// DecodedEvent is a Zonne program event. Data holds a pointer to one of the
+// *Event types from types.go.
+type DecodedEvent struct {
+	Name string      `json:"name"`
+	Data interface{} `json:"data"`
+}
+
+// DecodeEvent decodes an event from its data: an 8-byte discriminator followed
+// by the borsh-encoded event
+func DecodeEvent(data []byte) (*DecodedEvent, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("event data too short: %d bytes", len(data))
+	}
+
+	var discriminator [8]byte
+	copy(discriminator[:], data[:8])
+
+	for name, eventType := range sdkEvents {
+		if ComputeEventDiscriminator(name) != discriminator {
+			continue
+		}
+
+		event := reflect.New(eventType).Interface()
+		if err := borsh.Deserialize(event, data[8:]); err != nil {
+			return nil, fmt.Errorf("failed to deserialize %s event: %w", name, err)
+		}
+		return &DecodedEvent{Name: name, Data: event}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %v", ErrUnknownEvent, discriminator)
+}
+
+// DecodeEventsFromLogs decodes the events emitted by the client's program in
+// a transaction's log messages. Output of other programs, including programs
+// invoked by the Zonne program, is ignored, as are events the SDK does not know.
+func (c *Client) DecodeEventsFromLogs(logs []string) ([]*DecodedEvent, error) {
+	var (
+		stack  []string
+		events []*DecodedEvent
+	)
+
+	for _, line := range logs {
+		if strings.HasPrefix(line, programDataLogPrefix) {
+			if len(stack) == 0 || stack[len(stack)-1] != c.programID.String() {
+				continue
+			}
+
+			data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(line, programDataLogPrefix))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode program data: %w", err)
+			}
+			if len(data) < 8 {
+				continue
+			}
+
+			event, err := DecodeEvent(data)
+			if err != nil {
+				if errors.Is(err, ErrUnknownEvent) {
+					continue
+				}
+				return nil, err
+			}
+			events = append(events, event)
+			continue
+		}
+
+		if !strings.HasPrefix(line, programLogPrefix) {
+			continue
+		}
+
+		fields := strings.Fields(strings.TrimPrefix(line, programLogPrefix))
+		if len(fields) < 2 {
+			continue
+		}
+		if _, err := solana.PublicKeyFromBase58(fields[0]); err != nil {
+			continue
+		}
+
+		switch {
+		case fields[1] == "invoke":
+			stack = append(stack, fields[0])
+		case fields[1] == "success" || strings.HasPrefix(fields[1], "failed"):
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	return events, nil
+}
@@ -10,8 +10,11 @@ import (
 )
 
 func main() {
-	// Initialize the client with a local RPC endpoint
-	client := zonnegosdk.NewClient("http://localhost:8899")
+	// Initialize the client with the localnet profile
+	client, err := zonnegosdk.NewClientFromProfile(zonnegosdk.LocalnetProfile())
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
 
 	// Example keypairs (in production, load these securely)
 	gridAuthority := solana.MustPrivateKeyFromBase58("your-grid-authority-private-key-here")
@@ -157,7 +160,7 @@ func mintEnergyTokens(ctx context.Context, client *zonnegosdk.Client, producer s
 		Grid:          gridAuthority.PublicKey(),
 		Producer:      producer,
 		Amount:        1000, // 1000 energy tokens
-		EnergyType:    uint8(zonnegosdk.EnergyTypeSolar),
+		EnergyType:    zonnegosdk.EnergyTypeSolar,
 		GridAuthority: gridAuthority.PublicKey(),
 	}
 
@@ -175,8 +178,6 @@ func mintEnergyTokens(ctx context.Context, client *zonnegosdk.Client, producer s
 		return fmt.Errorf("failed to create transaction: %w", err)
 	}
 
-	transaction.String()
-
 	signature, err := client.SendAndConfirmTransaction(ctx, transaction, []solana.PrivateKey{gridAuthority})
 	if err != nil {
 		return fmt.Errorf("failed to send transaction: %w", err)
@@ -191,7 +192,7 @@ func listTokensForSale(ctx context.Context, client *zonnegosdk.Client, producer
 		Producer:      producer.PublicKey(),
 		Amount:        500,     // 500 energy tokens
 		PriceLamports: 1000000, // 0.001 SOL per token
-		EnergyType:    uint8(zonnegosdk.EnergyTypeSolar),
+		EnergyType:    zonnegosdk.EnergyTypeSolar,
 	}
 
 	instruction, err := client.ListTokensForSale(params)
@@ -221,7 +222,7 @@ func buyTokens(ctx context.Context, client *zonnegosdk.Client, buyer solana.Priv
 	// These parameters should match the listing created above
 	amount := uint64(500)
 	priceLamports := uint64(1000000)
-	energyType := uint8(zonnegosdk.EnergyTypeSolar)
+	energyType := zonnegosdk.EnergyTypeSolar
 
 	instruction, err := client.BuyTokens(buyer.PublicKey(), producer, amount, priceLamports, energyType)
 	if err != nil {
@@ -12,14 +12,17 @@ import (
 
 func main() {
 	// Initialize the Zonne SDK client
-	client := zonnegosdk.NewClient("https://api.devnet.solana.com", "Aw4Ef9sT3VBv7FXo1qWYR4CQN7LDuTkCcQQC3mxrjwab")
+	client, err := zonnegosdk.NewClientFromProfile(zonnegosdk.DevnetProfile())
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
 
 	// Example parameters for minting energy tokens
 	params := zonnegosdk.MintRecordCreationParams{
 		Grid:          solana.MustPublicKeyFromBase58("11111111111111111111111111111112"), // Example grid pubkey
 		Producer:      solana.MustPublicKeyFromBase58("11111111111111111111111111111113"), // Example producer pubkey
 		Amount:        1000,                                                               // 1000 kWh
-		EnergyType:    uint8(zonnegosdk.EnergyTypeSolar),
+		EnergyType:    zonnegosdk.EnergyTypeSolar,
 		GridAuthority: solana.MustPublicKeyFromBase58("11111111111111111111111111111114"), // Example grid authority
 	}
 
@@ -98,7 +101,7 @@ func demonstrateOtherOperations(client *zonnegosdk.Client, payer solana.PublicKe
 		Producer:      solana.MustPublicKeyFromBase58("22222222222222222222222222222222"),
 		Amount:        500,
 		PriceLamports: 1000000, // 0.001 SOL per token
-		EnergyType:    uint8(zonnegosdk.EnergyTypeSolar),
+		EnergyType:    zonnegosdk.EnergyTypeSolar,
 	}
 
 	listingInstruction, err := client.ListTokensForSale(listingParams)
@@ -120,7 +123,7 @@ func demonstrateOtherOperations(client *zonnegosdk.Client, payer solana.PublicKe
 		solana.MustPublicKeyFromBase58("22222222222222222222222222222222"), // producer
 		500,     // amount
 		1000000, // price
-		uint8(zonnegosdk.EnergyTypeSolar),
+		zonnegosdk.EnergyTypeSolar,
 	)
 	if err != nil {
 		log.Printf("Failed to create buy instruction: %v", err)
@@ -13,7 +13,10 @@ import (
 // MarketplaceDemo demonstrates a complete energy marketplace workflow
 func main() {
 	// Initialize client
-	client := zonnegosdk.NewClient("http://localhost:8899")
+	client, err := zonnegosdk.NewClientFromProfile(zonnegosdk.LocalnetProfile())
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
 	ctx := context.Background()
 
 	// Demo keypairs (replace with actual keypairs in production)
@@ -106,7 +109,7 @@ func generateEnergy(ctx context.Context, client *zonnegosdk.Client, gridAuth, pr
 		Grid:          gridAuth.PublicKey(),
 		Producer:      prod1.PublicKey(),
 		Amount:        2000, // 2000 kWh
-		EnergyType:    uint8(zonnegosdk.EnergyTypeSolar),
+		EnergyType:    zonnegosdk.EnergyTypeSolar,
 		GridAuthority: gridAuth.PublicKey(),
 	}
 	if err := executeTransaction(ctx, client, "Producer 1: Generate Solar Energy", func() (solana.Instruction, error) {
@@ -120,7 +123,7 @@ func generateEnergy(ctx context.Context, client *zonnegosdk.Client, gridAuth, pr
 		Grid:          gridAuth.PublicKey(),
 		Producer:      prod2.PublicKey(),
 		Amount:        1500, // 1500 kWh
-		EnergyType:    uint8(zonnegosdk.EnergyTypeWind),
+		EnergyType:    zonnegosdk.EnergyTypeWind,
 		GridAuthority: gridAuth.PublicKey(),
 	}
 	if err := executeTransaction(ctx, client, "Producer 2: Generate Wind Energy", func() (solana.Instruction, error) {
@@ -141,7 +144,7 @@ func createListings(ctx context.Context, client *zonnegosdk.Client, prod1, prod2
 		Producer:      prod1.PublicKey(),
 		Amount:        1000,   // 1000 kWh
 		PriceLamports: 500000, // 0.0005 SOL per kWh
-		EnergyType:    uint8(zonnegosdk.EnergyTypeSolar),
+		EnergyType:    zonnegosdk.EnergyTypeSolar,
 	}
 	if err := executeTransaction(ctx, client, "Producer 1: List Solar Energy", func() (solana.Instruction, error) {
 		return client.ListTokensForSale(solarListing)
@@ -154,7 +157,7 @@ func createListings(ctx context.Context, client *zonnegosdk.Client, prod1, prod2
 		Producer:      prod2.PublicKey(),
 		Amount:        800,    // 800 kWh
 		PriceLamports: 400000, // 0.0004 SOL per kWh (cheaper than solar)
-		EnergyType:    uint8(zonnegosdk.EnergyTypeWind),
+		EnergyType:    zonnegosdk.EnergyTypeWind,
 	}
 	if err := executeTransaction(ctx, client, "Producer 2: List Wind Energy", func() (solana.Instruction, error) {
 		return client.ListTokensForSale(windListing)
@@ -171,14 +174,14 @@ func purchaseEnergy(ctx context.Context, client *zonnegosdk.Client, cons1, cons2
 
 	// Consumer 1: Buy solar energy from Producer 1
 	if err := executeTransaction(ctx, client, "Consumer 1: Buy Solar Energy", func() (solana.Instruction, error) {
-		return client.BuyTokens(cons1.PublicKey(), prod1.PublicKey(), 1000, 500000, uint8(zonnegosdk.EnergyTypeSolar))
+		return client.BuyTokens(cons1.PublicKey(), prod1.PublicKey(), 1000, 500000, zonnegosdk.EnergyTypeSolar)
 	}, []solana.PrivateKey{cons1}); err != nil {
 		return err
 	}
 
 	// Consumer 2: Buy wind energy from Producer 2
 	if err := executeTransaction(ctx, client, "Consumer 2: Buy Wind Energy", func() (solana.Instruction, error) {
-		return client.BuyTokens(cons2.PublicKey(), prod2.PublicKey(), 800, 400000, uint8(zonnegosdk.EnergyTypeWind))
+		return client.BuyTokens(cons2.PublicKey(), prod2.PublicKey(), 800, 400000, zonnegosdk.EnergyTypeWind)
 	}, []solana.PrivateKey{cons2}); err != nil {
 		return err
 	}
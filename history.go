@@ -0,0 +1,118 @@
+package zonnegosdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// historyPageSize is the number of signatures requested per page of history
+const historyPageSize = 1000
+
+// TransactionEvents holds the Zonne events emitted by one transaction
+type TransactionEvents struct {
+	Signature solana.Signature `json:"signature"`
+	Slot      uint64           `json:"slot"`
+	BlockTime time.Time        `json:"block_time"`
+	Events    []*DecodedEvent  `json:"events"`
+}
+
+// GetEventHistory fetches every successful transaction that references the
+// address and decodes the Zonne events each emitted. The result is ordered
+// oldest first; transactions without Zonne events are omitted.
+func (c *Client) GetEventHistory(ctx context.Context, address solana.PublicKey) ([]TransactionEvents, error) {
+	signatures, err := c.signaturesForAddress(ctx, address, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	var history []TransactionEvents
+	for i := len(signatures) - 1; i >= 0; i-- {
+		signature := signatures[i]
+		if signature.Err != nil {
+			continue
+		}
+
+		events, err := c.GetTransactionEvents(ctx, signature.Signature)
+		if err != nil {
+			return nil, err
+		}
+		if len(events.Events) > 0 {
+			history = append(history, *events)
+		}
+	}
+
+	return history, nil
+}
+
+// signaturesForAddress fetches the signature of every finalized transaction
+// that references the address, newest first. Unless since is zero, paging
+// stops at the first page reaching back before since, so older signatures
+// may be missing.
+func (c *Client) signaturesForAddress(ctx context.Context, address solana.PublicKey, since time.Time) ([]*rpc.TransactionSignature, error) {
+	var signatures []*rpc.TransactionSignature
+
+	limit := historyPageSize
+	opts := &rpc.GetSignaturesForAddressOpts{
+		Limit:      &limit,
+		Commitment: rpc.CommitmentFinalized,
+	}
+	for {
+		page, err := c.rpcClient.GetSignaturesForAddressWithOpts(ctx, address, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get signatures for %s: %w", address, err)
+		}
+		signatures = append(signatures, page...)
+		if len(page) < limit {
+			break
+		}
+		if oldest := page[len(page)-1].BlockTime; !since.IsZero() && oldest != nil && oldest.Time().Before(since) {
+			break
+		}
+		opts.Before = page[len(page)-1].Signature
+	}
+
+	return signatures, nil
+}
+
+// GetTransactionEvents fetches a confirmed transaction and decodes the Zonne events it emitted
+func (c *Client) GetTransactionEvents(ctx context.Context, signature solana.Signature) (*TransactionEvents, error) {
+	result, err := c.getFinalizedTransaction(ctx, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	events := &TransactionEvents{
+		Signature: signature,
+		Slot:      result.Slot,
+	}
+	if result.BlockTime != nil {
+		events.BlockTime = result.BlockTime.Time().UTC()
+	}
+	if result.Meta == nil {
+		return events, nil
+	}
+
+	events.Events, err = c.DecodeEventsFromLogs(result.Meta.LogMessages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode events of %s: %w", signature, err)
+	}
+	return events, nil
+}
+
+// getFinalizedTransaction fetches a finalized transaction
+func (c *Client) getFinalizedTransaction(ctx context.Context, signature solana.Signature) (*rpc.GetTransactionResult, error) {
+	maxVersion := uint64(0)
+	result, err := c.rpcClient.GetTransaction(ctx, signature, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     rpc.CommitmentFinalized,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", signature, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,430 @@
+package zonnegosdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// IDL is an Anchor IDL document. Both the legacy layout (camelCase names,
+// isMut/isSigner, inline account types) and the Anchor 0.30 layout (snake_case
+// names, explicit discriminators, shared types section) are accepted.
+type IDL struct {
+	Address      string           `json:"address,omitempty"`
+	Name         string           `json:"name,omitempty"`
+	Version      string           `json:"version,omitempty"`
+	Metadata     *IDLMetadata     `json:"metadata,omitempty"`
+	Instructions []IDLInstruction `json:"instructions"`
+	Accounts     []IDLTypeDef     `json:"accounts,omitempty"`
+	Events       []IDLEvent       `json:"events,omitempty"`
+	Errors       []IDLError       `json:"errors,omitempty"`
+	Types        []IDLTypeDef     `json:"types,omitempty"`
+}
+
+// IDLMetadata holds the program metadata of an Anchor 0.30 IDL
+type IDLMetadata struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Spec    string `json:"spec,omitempty"`
+}
+
+// IDLInstruction describes a program instruction
+type IDLInstruction struct {
+	Name          string               `json:"name"`
+	Discriminator []int                `json:"discriminator,omitempty"`
+	Accounts      []IDLInstructionItem `json:"accounts"`
+	Args          []IDLField           `json:"args"`
+}
+
+// IDLInstructionItem describes an account passed to an instruction
+type IDLInstructionItem struct {
+	Name     string `json:"name"`
+	Writable bool   `json:"writable,omitempty"`
+	Signer   bool   `json:"signer,omitempty"`
+	IsMut    bool   `json:"isMut,omitempty"`
+	IsSigner bool   `json:"isSigner,omitempty"`
+	Address  string `json:"address,omitempty"`
+}
+
+// IsWritable reports whether the account is writable in either IDL layout
+func (a IDLInstructionItem) IsWritable() bool {
+	return a.Writable || a.IsMut
+}
+
+// IsSignerAccount reports whether the account signs in either IDL layout
+func (a IDLInstructionItem) IsSignerAccount() bool {
+	return a.Signer || a.IsSigner
+}
+
+// IDLField is a named, typed struct field or instruction argument
+type IDLField struct {
+	Name string          `json:"name"`
+	Type json.RawMessage `json:"type"`
+}
+
+// TypeName returns the primitive type name of the field, or "" for compound types
+func (f IDLField) TypeName() string {
+	var name string
+	if err := json.Unmarshal(f.Type, &name); err != nil {
+		return ""
+	}
+	return name
+}
+
+// IDLTypeDef is a named type definition, used for accounts and shared types
+type IDLTypeDef struct {
+	Name          string       `json:"name"`
+	Discriminator []int        `json:"discriminator,omitempty"`
+	Type          *IDLTypeBody `json:"type,omitempty"`
+}
+
+// IDLTypeBody is the body of a type definition
+type IDLTypeBody struct {
+	Kind   string     `json:"kind"`
+	Fields []IDLField `json:"fields,omitempty"`
+}
+
+// IDLEvent describes a program event
+type IDLEvent struct {
+	Name          string     `json:"name"`
+	Discriminator []int      `json:"discriminator,omitempty"`
+	Fields        []IDLField `json:"fields,omitempty"`
+}
+
+// IDLError describes a custom program error
+type IDLError struct {
+	Code uint32 `json:"code"`
+	Name string `json:"name"`
+	Msg  string `json:"msg,omitempty"`
+}
+
+// ParseIDL parses an Anchor IDL JSON document
+func ParseIDL(data []byte) (*IDL, error) {
+	var idl IDL
+	if err := json.Unmarshal(data, &idl); err != nil {
+		return nil, fmt.Errorf("failed to parse IDL: %w", err)
+	}
+	if len(idl.Instructions) == 0 {
+		return nil, fmt.Errorf("IDL has no instructions")
+	}
+	return &idl, nil
+}
+
+// ProgramName returns the program name from either IDL layout
+func (idl *IDL) ProgramName() string {
+	if idl.Metadata != nil && idl.Metadata.Name != "" {
+		return idl.Metadata.Name
+	}
+	return idl.Name
+}
+
+// InstructionDiscriminator returns the discriminator of an instruction,
+// computing it from the instruction name when the IDL does not carry one
+func (idl *IDL) InstructionDiscriminator(instruction IDLInstruction) [8]byte {
+	return idlDiscriminator(instruction.Discriminator, instructionNamespace, ToSnakeCase(instruction.Name))
+}
+
+// AccountDiscriminator returns the discriminator of an account type
+func (idl *IDL) AccountDiscriminator(account IDLTypeDef) [8]byte {
+	return idlDiscriminator(account.Discriminator, accountNamespace, account.Name)
+}
+
+// EventDiscriminator returns the discriminator of an event
+func (idl *IDL) EventDiscriminator(event IDLEvent) [8]byte {
+	return idlDiscriminator(event.Discriminator, eventNamespace, event.Name)
+}
+
+// AccountFields returns the fields of an account type, looking in the types
+// section when the account definition does not inline them
+func (idl *IDL) AccountFields(account IDLTypeDef) ([]IDLField, error) {
+	if account.Type != nil {
+		return account.Type.Fields, nil
+	}
+	return idl.typeFields(account.Name)
+}
+
+// EventFields returns the fields of an event
+func (idl *IDL) EventFields(event IDLEvent) ([]IDLField, error) {
+	if len(event.Fields) > 0 {
+		return event.Fields, nil
+	}
+	return idl.typeFields(event.Name)
+}
+
+func (idl *IDL) typeFields(name string) ([]IDLField, error) {
+	for _, t := range idl.Types {
+		if t.Name == name && t.Type != nil {
+			return t.Type.Fields, nil
+		}
+	}
+	return nil, fmt.Errorf("type %s not found in IDL", name)
+}
+
+// IDLFieldSize returns the borsh-encoded size of a primitive IDL type
+func IDLFieldSize(typeName string) (int, error) {
+	switch typeName {
+	case "bool", "u8", "i8":
+		return 1, nil
+	case "u16", "i16":
+		return 2, nil
+	case "u32", "i32", "f32":
+		return 4, nil
+	case "u64", "i64", "f64":
+		return 8, nil
+	case "u128", "i128":
+		return 16, nil
+	case "pubkey", "publicKey":
+		return 32, nil
+	default:
+		return 0, fmt.Errorf("unsupported IDL type %q", typeName)
+	}
+}
+
+// ToSnakeCase converts a camelCase or PascalCase IDL name to snake_case
+func ToSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// idlDiscriminator returns an explicit IDL discriminator, or computes it
+func idlDiscriminator(explicit []int, namespace, name string) [8]byte {
+	if len(explicit) != 8 {
+		return anchorDiscriminator(namespace, name)
+	}
+	var d [8]byte
+	for i, b := range explicit {
+		d[i] = byte(b)
+	}
+	return d
+}
+
+// IDLMismatch describes a disagreement between an IDL and the SDK
+type IDLMismatch struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Detail string `json:"detail"`
+}
+
+// String renders the mismatch for display
+func (m IDLMismatch) String() string {
+	return fmt.Sprintf("%s %s: %s", m.Kind, m.Name, m.Detail)
+}
+
+// sdkAccountSizes maps IDL account names to the SDK's size constants
+var sdkAccountSizes = map[string]int{
+	"GridAccount":     GridAccountSize,
+	"ProducerAccount": ProducerAccountSize,
+	"ConsumerAccount": ConsumerAccountSize,
+	"MintRecord":      MintRecordSize,
+	"ListingAccount":  ListingAccountSize,
+}
+
+// sdkEvents maps IDL event names to the SDK's event types
+var sdkEvents = map[string]reflect.Type{
+	"GridInitialized":     reflect.TypeOf(GridInitializedEvent{}),
+	"ProducerInitialized": reflect.TypeOf(ProducerInitializedEvent{}),
+	"ConsumerInitialized": reflect.TypeOf(ConsumerInitializedEvent{}),
+	"TokensMinted":        reflect.TypeOf(TokensMintedEvent{}),
+	"TokensListed":        reflect.TypeOf(TokensListedEvent{}),
+	"ListingCancelled":    reflect.TypeOf(ListingCancelledEvent{}),
+	"TokensPurchased":     reflect.TypeOf(TokensPurchasedEvent{}),
+	"ConsumptionMinted":   reflect.TypeOf(ConsumptionMintedEvent{}),
+}
+
+// CompareIDL compares an IDL against the hand-written discriminators,
+// account sizes, instruction account orderings, argument layouts and event
+// types of the SDK, returning every disagreement found
+func CompareIDL(idl *IDL) []IDLMismatch {
+	return compareIDL(idl, defaultDiscriminators())
+}
+
+// compareIDL compares an IDL against the SDK using the given instruction discriminators
+func compareIDL(idl *IDL, discriminators map[string][8]byte) []IDLMismatch {
+	var mismatches []IDLMismatch
+	add := func(kind, name, format string, args ...interface{}) {
+		mismatches = append(mismatches, IDLMismatch{Kind: kind, Name: name, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	// Instructions are matched by discriminator, so a fork that renamed an
+	// instruction matches the SDK instruction configured with its name
+	sdkNames := make(map[[8]byte]string, len(discriminators))
+	for sdkName, d := range discriminators {
+		sdkNames[d] = sdkName
+	}
+
+	seen := make(map[string]bool)
+	for _, instruction := range idl.Instructions {
+		name := ToSnakeCase(instruction.Name)
+		d := idl.InstructionDiscriminator(instruction)
+		sdkName, ok := sdkNames[d]
+		if !ok {
+			if findInstructionLayout(name) == nil {
+				add("instruction", name, "not implemented by the SDK")
+				continue
+			}
+			sdkName = name
+			add("instruction", name, "discriminator %v, SDK has %v", d, discriminators[name])
+		}
+		seen[sdkName] = true
+		layout := findInstructionLayout(sdkName)
+
+		metas, data, err := sampleInstruction(sdkName)
+		if err != nil {
+			add("instruction", name, "failed to build sample instruction: %v", err)
+			continue
+		}
+
+		if len(instruction.Accounts) != len(layout.accounts) {
+			add("instruction", name, "%d accounts, SDK has %d", len(instruction.Accounts), len(layout.accounts))
+		} else {
+			for i, account := range instruction.Accounts {
+				accountName := ToSnakeCase(account.Name)
+				if accountName != layout.accounts[i] {
+					add("instruction", name, "account #%d is %s, SDK has %s", i, accountName, layout.accounts[i])
+				}
+				if account.IsWritable() != metas[i].IsWritable {
+					add("instruction", name, "account %s writable=%t, SDK has %t", accountName, account.IsWritable(), metas[i].IsWritable)
+				}
+				if account.IsSignerAccount() != metas[i].IsSigner {
+					add("instruction", name, "account %s signer=%t, SDK has %t", accountName, account.IsSignerAccount(), metas[i].IsSigner)
+				}
+			}
+		}
+
+		argsSize := 0
+		for _, arg := range instruction.Args {
+			size, err := IDLFieldSize(arg.TypeName())
+			if err != nil {
+				add("instruction", name, "argument %s: %v", arg.Name, err)
+				continue
+			}
+			argsSize += size
+		}
+		if argsSize != len(data)-8 {
+			add("instruction", name, "arguments encode to %d bytes, SDK encodes %d", argsSize, len(data)-8)
+		}
+	}
+	for _, layout := range instructionLayouts {
+		if !seen[layout.name] {
+			add("instruction", layout.name, "missing from IDL")
+		}
+	}
+
+	for _, account := range idl.Accounts {
+		sdkSize, ok := sdkAccountSizes[account.Name]
+		if !ok {
+			add("account", account.Name, "not implemented by the SDK")
+			continue
+		}
+
+		fields, err := idl.AccountFields(account)
+		if err != nil {
+			add("account", account.Name, "%v", err)
+			continue
+		}
+
+		size := AccountDiscriminatorSize
+		for _, field := range fields {
+			fieldSize, err := IDLFieldSize(field.TypeName())
+			if err != nil {
+				add("account", account.Name, "field %s: %v", field.Name, err)
+				continue
+			}
+			size += fieldSize
+		}
+		if size != sdkSize {
+			add("account", account.Name, "size %d, SDK has %d", size, sdkSize)
+		}
+	}
+
+	for _, event := range idl.Events {
+		eventType, ok := sdkEvents[event.Name]
+		if !ok {
+			add("event", event.Name, "not implemented by the SDK")
+			continue
+		}
+
+		fields, err := idl.EventFields(event)
+		if err != nil {
+			add("event", event.Name, "%v", err)
+			continue
+		}
+
+		if len(fields) != eventType.NumField() {
+			add("event", event.Name, "%d fields, SDK has %d", len(fields), eventType.NumField())
+			continue
+		}
+		for i, field := range fields {
+			tag := strings.Split(eventType.Field(i).Tag.Get("json"), ",")[0]
+			if ToSnakeCase(field.Name) != tag {
+				add("event", event.Name, "field #%d is %s, SDK has %s", i, ToSnakeCase(field.Name), tag)
+			}
+		}
+	}
+
+	return mismatches
+}
+
+// findInstructionLayout returns the decoder layout for an instruction name
+func findInstructionLayout(name string) *instructionLayout {
+	for i := range instructionLayouts {
+		if instructionLayouts[i].name == name {
+			return &instructionLayouts[i]
+		}
+	}
+	return nil
+}
+
+// sampleInstruction builds an instruction with placeholder keys so that its
+// account flags and encoded data can be compared against an IDL
+func sampleInstruction(name string) ([]*solana.AccountMeta, []byte, error) {
+	c := NewClientWithCustomProgram("", solana.SystemProgramID)
+	key := solana.MustPublicKeyFromBase58("SysvarC1ock11111111111111111111111111111111")
+
+	var (
+		instruction solana.Instruction
+		err         error
+	)
+	switch name {
+	case InstructionInitializeGrid:
+		instruction, err = c.InitializeGrid(GridAccountCreationParams{Grid: key, Authority: key})
+	case InstructionInitializeProducer:
+		instruction, err = c.InitializeProducer(ProducerAccountCreationParams{Producer: key, Authority: key})
+	case InstructionInitializeConsumer:
+		instruction, err = c.InitializeConsumer(ConsumerAccountCreationParams{Consumer: key, Authority: key})
+	case InstructionMintEnergyTokens:
+		instruction, err = c.MintEnergyTokens(MintRecordCreationParams{Grid: key, Producer: key, Amount: 1, GridAuthority: key})
+	case InstructionListTokensForSale:
+		instruction, err = c.ListTokensForSale(ListingAccountCreationParams{Producer: key, Amount: 1, PriceLamports: 1})
+	case InstructionCancelListing:
+		instruction, err = c.CancelListing(key, 1, 1, 0)
+	case InstructionBuyTokens:
+		instruction, err = c.BuyTokens(key, key, 1, 1, 0)
+	case InstructionMintConsumptionTokens:
+		instruction, err = c.MintConsumptionTokens(key, key, key, 1)
+	default:
+		return nil, nil, fmt.Errorf("unknown instruction %s", name)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := instruction.Data()
+	if err != nil {
+		return nil, nil, err
+	}
+	return instruction.Accounts(), data, nil
+}
@@ -0,0 +1,11 @@
+// Package idl contains Go bindings generated from the Zonne program's Anchor
+// IDL (zonne.json): discriminators, borsh account, argument and event structs,
+// account-meta builders and program error codes.
+//
+// Regenerate after updating zonne.json with go generate. To verify that the
+// hand-written SDK still agrees with the IDL, run
+//
+//	go run ./cmd/idlgen -idl idl/zonne.json -out idl/zonne_generated.go -check
+package idl
+
+//go:generate go run ../cmd/idlgen -idl zonne.json -out zonne_generated.go -pkg idl
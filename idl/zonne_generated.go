@@ -0,0 +1,369 @@
+// Code generated by idlgen from zonne.json. DO NOT EDIT.
+
+package idl
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/near/borsh-go"
+)
+
+// ProgramID is the program address declared by the IDL
+var ProgramID = solana.MustPublicKeyFromBase58("Aw4Ef9sT3VBv7FXo1qWYR4CQN7LDuTkCcQQC3mxrjwab")
+
+// Instruction discriminators
+var (
+	InitializeGridDiscriminator        = [8]byte{30, 224, 57, 59, 226, 1, 253, 219}
+	InitializeProducerDiscriminator    = [8]byte{168, 203, 156, 75, 245, 233, 21, 201}
+	InitializeConsumerDiscriminator    = [8]byte{228, 176, 96, 150, 209, 224, 65, 98}
+	MintEnergyTokensDiscriminator      = [8]byte{147, 199, 3, 69, 8, 89, 72, 226}
+	ListTokensForSaleDiscriminator     = [8]byte{213, 6, 33, 225, 91, 199, 59, 195}
+	CancelListingDiscriminator         = [8]byte{41, 183, 50, 232, 230, 233, 157, 70}
+	BuyTokensDiscriminator             = [8]byte{189, 21, 230, 133, 247, 2, 110, 42}
+	MintConsumptionTokensDiscriminator = [8]byte{75, 241, 244, 71, 205, 59, 169, 126}
+)
+
+// Account discriminators
+var (
+	GridAccountDiscriminator     = [8]byte{230, 49, 69, 71, 26, 221, 176, 251}
+	ProducerAccountDiscriminator = [8]byte{157, 130, 169, 90, 169, 93, 143, 218}
+	ConsumerAccountDiscriminator = [8]byte{201, 248, 186, 170, 156, 117, 47, 209}
+	MintRecordDiscriminator      = [8]byte{47, 252, 142, 126, 241, 162, 116, 188}
+	ListingAccountDiscriminator  = [8]byte{59, 89, 136, 25, 21, 196, 183, 13}
+)
+
+// Event discriminators
+var (
+	GridInitializedEventDiscriminator     = [8]byte{186, 206, 196, 128, 181, 177, 29, 107}
+	ProducerInitializedEventDiscriminator = [8]byte{241, 66, 250, 50, 127, 235, 208, 74}
+	ConsumerInitializedEventDiscriminator = [8]byte{30, 176, 101, 101, 251, 56, 108, 220}
+	TokensMintedEventDiscriminator        = [8]byte{207, 212, 128, 194, 175, 54, 64, 24}
+	TokensListedEventDiscriminator        = [8]byte{254, 71, 121, 117, 34, 2, 135, 8}
+	ListingCancelledEventDiscriminator    = [8]byte{11, 46, 163, 10, 103, 80, 139, 194}
+	TokensPurchasedEventDiscriminator     = [8]byte{214, 119, 105, 186, 114, 205, 228, 181}
+	ConsumptionMintedEventDiscriminator   = [8]byte{36, 33, 41, 45, 8, 251, 120, 130}
+)
+
+// InitializeGridAccounts holds the accounts of the initialize_grid instruction
+type InitializeGridAccounts struct {
+	GridAccount solana.PublicKey
+	Grid        solana.PublicKey
+	Authority   solana.PublicKey
+}
+
+// AccountMetas returns the accounts of the initialize_grid instruction in program order
+func (a InitializeGridAccounts) AccountMetas() []*solana.AccountMeta {
+	return []*solana.AccountMeta{
+		{PublicKey: a.GridAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: a.Grid, IsWritable: false, IsSigner: false},
+		{PublicKey: a.Authority, IsWritable: true, IsSigner: true},
+		{PublicKey: solana.MustPublicKeyFromBase58("11111111111111111111111111111111"), IsWritable: false, IsSigner: false},
+	}
+}
+
+// NewInitializeGridInstruction builds the initialize_grid instruction
+func NewInitializeGridInstruction(programID solana.PublicKey, accounts InitializeGridAccounts) (solana.Instruction, error) {
+	data := append([]byte{}, InitializeGridDiscriminator[:]...)
+	return solana.NewInstruction(programID, accounts.AccountMetas(), data), nil
+}
+
+// InitializeProducerAccounts holds the accounts of the initialize_producer instruction
+type InitializeProducerAccounts struct {
+	ProducerAccount solana.PublicKey
+	Producer        solana.PublicKey
+	Authority       solana.PublicKey
+}
+
+// AccountMetas returns the accounts of the initialize_producer instruction in program order
+func (a InitializeProducerAccounts) AccountMetas() []*solana.AccountMeta {
+	return []*solana.AccountMeta{
+		{PublicKey: a.ProducerAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: a.Producer, IsWritable: false, IsSigner: false},
+		{PublicKey: a.Authority, IsWritable: true, IsSigner: true},
+		{PublicKey: solana.MustPublicKeyFromBase58("11111111111111111111111111111111"), IsWritable: false, IsSigner: false},
+	}
+}
+
+// NewInitializeProducerInstruction builds the initialize_producer instruction
+func NewInitializeProducerInstruction(programID solana.PublicKey, accounts InitializeProducerAccounts) (solana.Instruction, error) {
+	data := append([]byte{}, InitializeProducerDiscriminator[:]...)
+	return solana.NewInstruction(programID, accounts.AccountMetas(), data), nil
+}
+
+// InitializeConsumerAccounts holds the accounts of the initialize_consumer instruction
+type InitializeConsumerAccounts struct {
+	ConsumerAccount solana.PublicKey
+	Consumer        solana.PublicKey
+	Authority       solana.PublicKey
+}
+
+// AccountMetas returns the accounts of the initialize_consumer instruction in program order
+func (a InitializeConsumerAccounts) AccountMetas() []*solana.AccountMeta {
+	return []*solana.AccountMeta{
+		{PublicKey: a.ConsumerAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: a.Consumer, IsWritable: false, IsSigner: false},
+		{PublicKey: a.Authority, IsWritable: true, IsSigner: true},
+		{PublicKey: solana.MustPublicKeyFromBase58("11111111111111111111111111111111"), IsWritable: false, IsSigner: false},
+	}
+}
+
+// NewInitializeConsumerInstruction builds the initialize_consumer instruction
+func NewInitializeConsumerInstruction(programID solana.PublicKey, accounts InitializeConsumerAccounts) (solana.Instruction, error) {
+	data := append([]byte{}, InitializeConsumerDiscriminator[:]...)
+	return solana.NewInstruction(programID, accounts.AccountMetas(), data), nil
+}
+
+// MintEnergyTokensAccounts holds the accounts of the mint_energy_tokens instruction
+type MintEnergyTokensAccounts struct {
+	ProducerAccount solana.PublicKey
+	GridAccount     solana.PublicKey
+	MintRecord      solana.PublicKey
+	Producer        solana.PublicKey
+	GridAuthority   solana.PublicKey
+}
+
+// AccountMetas returns the accounts of the mint_energy_tokens instruction in program order
+func (a MintEnergyTokensAccounts) AccountMetas() []*solana.AccountMeta {
+	return []*solana.AccountMeta{
+		{PublicKey: a.ProducerAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: a.GridAccount, IsWritable: false, IsSigner: false},
+		{PublicKey: a.MintRecord, IsWritable: true, IsSigner: false},
+		{PublicKey: a.Producer, IsWritable: false, IsSigner: false},
+		{PublicKey: a.GridAuthority, IsWritable: true, IsSigner: true},
+		{PublicKey: solana.MustPublicKeyFromBase58("11111111111111111111111111111111"), IsWritable: false, IsSigner: false},
+	}
+}
+
+// MintEnergyTokensArgs holds the arguments of the mint_energy_tokens instruction
+type MintEnergyTokensArgs struct {
+	Amount     uint64 `borsh:"amount"`
+	EnergyType uint8  `borsh:"energy_type"`
+}
+
+// NewMintEnergyTokensInstruction builds the mint_energy_tokens instruction
+func NewMintEnergyTokensInstruction(programID solana.PublicKey, accounts MintEnergyTokensAccounts, args MintEnergyTokensArgs) (solana.Instruction, error) {
+	serializedData, err := borsh.Serialize(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize mint_energy_tokens arguments: %w", err)
+	}
+	data := append(MintEnergyTokensDiscriminator[:], serializedData...)
+	return solana.NewInstruction(programID, accounts.AccountMetas(), data), nil
+}
+
+// ListTokensForSaleAccounts holds the accounts of the list_tokens_for_sale instruction
+type ListTokensForSaleAccounts struct {
+	ProducerAccount solana.PublicKey
+	ListingAccount  solana.PublicKey
+	Producer        solana.PublicKey
+}
+
+// AccountMetas returns the accounts of the list_tokens_for_sale instruction in program order
+func (a ListTokensForSaleAccounts) AccountMetas() []*solana.AccountMeta {
+	return []*solana.AccountMeta{
+		{PublicKey: a.ProducerAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: a.ListingAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: a.Producer, IsWritable: true, IsSigner: true},
+		{PublicKey: solana.MustPublicKeyFromBase58("11111111111111111111111111111111"), IsWritable: false, IsSigner: false},
+	}
+}
+
+// ListTokensForSaleArgs holds the arguments of the list_tokens_for_sale instruction
+type ListTokensForSaleArgs struct {
+	Amount        uint64 `borsh:"amount"`
+	PriceLamports uint64 `borsh:"price_lamports"`
+	EnergyType    uint8  `borsh:"energy_type"`
+}
+
+// NewListTokensForSaleInstruction builds the list_tokens_for_sale instruction
+func NewListTokensForSaleInstruction(programID solana.PublicKey, accounts ListTokensForSaleAccounts, args ListTokensForSaleArgs) (solana.Instruction, error) {
+	serializedData, err := borsh.Serialize(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize list_tokens_for_sale arguments: %w", err)
+	}
+	data := append(ListTokensForSaleDiscriminator[:], serializedData...)
+	return solana.NewInstruction(programID, accounts.AccountMetas(), data), nil
+}
+
+// CancelListingAccounts holds the accounts of the cancel_listing instruction
+type CancelListingAccounts struct {
+	ListingAccount  solana.PublicKey
+	ProducerAccount solana.PublicKey
+	Producer        solana.PublicKey
+}
+
+// AccountMetas returns the accounts of the cancel_listing instruction in program order
+func (a CancelListingAccounts) AccountMetas() []*solana.AccountMeta {
+	return []*solana.AccountMeta{
+		{PublicKey: a.ListingAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: a.ProducerAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: a.Producer, IsWritable: true, IsSigner: true},
+	}
+}
+
+// NewCancelListingInstruction builds the cancel_listing instruction
+func NewCancelListingInstruction(programID solana.PublicKey, accounts CancelListingAccounts) (solana.Instruction, error) {
+	data := append([]byte{}, CancelListingDiscriminator[:]...)
+	return solana.NewInstruction(programID, accounts.AccountMetas(), data), nil
+}
+
+// BuyTokensAccounts holds the accounts of the buy_tokens instruction
+type BuyTokensAccounts struct {
+	ListingAccount  solana.PublicKey
+	ConsumerAccount solana.PublicKey
+	Producer        solana.PublicKey
+	Buyer           solana.PublicKey
+}
+
+// AccountMetas returns the accounts of the buy_tokens instruction in program order
+func (a BuyTokensAccounts) AccountMetas() []*solana.AccountMeta {
+	return []*solana.AccountMeta{
+		{PublicKey: a.ListingAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: a.ConsumerAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: a.Producer, IsWritable: true, IsSigner: false},
+		{PublicKey: a.Buyer, IsWritable: true, IsSigner: true},
+		{PublicKey: solana.MustPublicKeyFromBase58("11111111111111111111111111111111"), IsWritable: false, IsSigner: false},
+	}
+}
+
+// BuyTokensArgs holds the arguments of the buy_tokens instruction
+type BuyTokensArgs struct {
+	ListingID solana.PublicKey `borsh:"listing_id"`
+}
+
+// NewBuyTokensInstruction builds the buy_tokens instruction
+func NewBuyTokensInstruction(programID solana.PublicKey, accounts BuyTokensAccounts, args BuyTokensArgs) (solana.Instruction, error) {
+	serializedData, err := borsh.Serialize(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize buy_tokens arguments: %w", err)
+	}
+	data := append(BuyTokensDiscriminator[:], serializedData...)
+	return solana.NewInstruction(programID, accounts.AccountMetas(), data), nil
+}
+
+// MintConsumptionTokensAccounts holds the accounts of the mint_consumption_tokens instruction
+type MintConsumptionTokensAccounts struct {
+	ConsumerAccount solana.PublicKey
+	GridAccount     solana.PublicKey
+	GridAuthority   solana.PublicKey
+}
+
+// AccountMetas returns the accounts of the mint_consumption_tokens instruction in program order
+func (a MintConsumptionTokensAccounts) AccountMetas() []*solana.AccountMeta {
+	return []*solana.AccountMeta{
+		{PublicKey: a.ConsumerAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: a.GridAccount, IsWritable: false, IsSigner: false},
+		{PublicKey: a.GridAuthority, IsWritable: true, IsSigner: true},
+	}
+}
+
+// MintConsumptionTokensArgs holds the arguments of the mint_consumption_tokens instruction
+type MintConsumptionTokensArgs struct {
+	Amount uint64 `borsh:"amount"`
+}
+
+// NewMintConsumptionTokensInstruction builds the mint_consumption_tokens instruction
+func NewMintConsumptionTokensInstruction(programID solana.PublicKey, accounts MintConsumptionTokensAccounts, args MintConsumptionTokensArgs) (solana.Instruction, error) {
+	serializedData, err := borsh.Serialize(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize mint_consumption_tokens arguments: %w", err)
+	}
+	data := append(MintConsumptionTokensDiscriminator[:], serializedData...)
+	return solana.NewInstruction(programID, accounts.AccountMetas(), data), nil
+}
+
+// Account sizes, including the 8-byte discriminator
+const (
+	GridAccountSize     = 8 + 1
+	ProducerAccountSize = 8 + 8
+	ConsumerAccountSize = 8 + 8
+	MintRecordSize      = 8 + 81
+	ListingAccountSize  = 8 + 58
+)
+
+// GridAccount is the GridAccount account
+type GridAccount struct {
+	IsActive bool `borsh:"is_active"`
+}
+
+// ProducerAccount is the ProducerAccount account
+type ProducerAccount struct {
+	Balance uint64 `borsh:"balance"`
+}
+
+// ConsumerAccount is the ConsumerAccount account
+type ConsumerAccount struct {
+	Consumption uint64 `borsh:"consumption"`
+}
+
+// MintRecord is the MintRecord account
+type MintRecord struct {
+	Grid       solana.PublicKey `borsh:"grid"`
+	Producer   solana.PublicKey `borsh:"producer"`
+	Amount     uint64           `borsh:"amount"`
+	EnergyType uint8            `borsh:"energy_type"`
+	Timestamp  int64            `borsh:"timestamp"`
+}
+
+// ListingAccount is the ListingAccount account
+type ListingAccount struct {
+	Producer      solana.PublicKey `borsh:"producer"`
+	Amount        uint64           `borsh:"amount"`
+	PriceLamports uint64           `borsh:"price_lamports"`
+	EnergyType    uint8            `borsh:"energy_type"`
+	IsActive      bool             `borsh:"is_active"`
+	CreatedAt     int64            `borsh:"created_at"`
+}
+
+// GridInitializedEvent is the GridInitialized event
+type GridInitializedEvent struct {
+	Grid solana.PublicKey `borsh:"grid"`
+}
+
+// ProducerInitializedEvent is the ProducerInitialized event
+type ProducerInitializedEvent struct {
+	Producer solana.PublicKey `borsh:"producer"`
+}
+
+// ConsumerInitializedEvent is the ConsumerInitialized event
+type ConsumerInitializedEvent struct {
+	Consumer solana.PublicKey `borsh:"consumer"`
+}
+
+// TokensMintedEvent is the TokensMinted event
+type TokensMintedEvent struct {
+	Producer   solana.PublicKey `borsh:"producer"`
+	Amount     uint64           `borsh:"amount"`
+	EnergyType uint8            `borsh:"energy_type"`
+}
+
+// TokensListedEvent is the TokensListed event
+type TokensListedEvent struct {
+	ListingID     solana.PublicKey `borsh:"listing_id"`
+	Producer      solana.PublicKey `borsh:"producer"`
+	Amount        uint64           `borsh:"amount"`
+	PriceLamports uint64           `borsh:"price_lamports"`
+	EnergyType    uint8            `borsh:"energy_type"`
+}
+
+// ListingCancelledEvent is the ListingCancelled event
+type ListingCancelledEvent struct {
+	ListingID solana.PublicKey `borsh:"listing_id"`
+	Producer  solana.PublicKey `borsh:"producer"`
+	Amount    uint64           `borsh:"amount"`
+}
+
+// TokensPurchasedEvent is the TokensPurchased event
+type TokensPurchasedEvent struct {
+	ListingID     solana.PublicKey `borsh:"listing_id"`
+	Buyer         solana.PublicKey `borsh:"buyer"`
+	Producer      solana.PublicKey `borsh:"producer"`
+	Amount        uint64           `borsh:"amount"`
+	PriceLamports uint64           `borsh:"price_lamports"`
+}
+
+// ConsumptionMintedEvent is the ConsumptionMinted event
+type ConsumptionMintedEvent struct {
+	Consumer solana.PublicKey `borsh:"consumer"`
+	Amount   uint64           `borsh:"amount"`
+}
@@ -0,0 +1,154 @@
+package zonnegosdk
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// IDLAccountSeed is the seed Anchor uses to derive a program's IDL account
+const IDLAccountSeed = "anchor:idl"
+
+// IDL account header: discriminator (8) + authority (32) + data length (4)
+const idlAccountHeaderSize = AccountDiscriminatorSize + 32 + 4
+
+// IDLCompatibilityReport is the result of comparing an on-chain IDL with the SDK
+type IDLCompatibilityReport struct {
+	ProgramID      solana.PublicKey `json:"program_id"`
+	IDLAddress     solana.PublicKey `json:"idl_address"`
+	Authority      solana.PublicKey `json:"authority"`
+	ProgramName    string           `json:"program_name"`
+	ProgramVersion string           `json:"program_version"`
+	Mismatches     []IDLMismatch    `json:"mismatches"`
+}
+
+// Compatible reports whether the SDK agrees with the on-chain IDL
+func (r *IDLCompatibilityReport) Compatible() bool {
+	return len(r.Mismatches) == 0
+}
+
+// String renders the report for display
+func (r *IDLCompatibilityReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Program:     %s\n", r.ProgramID)
+	fmt.Fprintf(&b, "IDL Account: %s\n", r.IDLAddress)
+	fmt.Fprintf(&b, "IDL:         %s %s\n", r.ProgramName, r.ProgramVersion)
+	if r.Compatible() {
+		b.WriteString("Compatible:  yes\n")
+		return b.String()
+	}
+	b.WriteString("Compatible:  no\n")
+	for _, mismatch := range r.Mismatches {
+		fmt.Fprintf(&b, "  - %s\n", mismatch)
+	}
+	return b.String()
+}
+
+// DeriveIDLAddress derives the address of the program's Anchor IDL account
+func (c *Client) DeriveIDLAddress() (solana.PublicKey, error) {
+	base, _, err := solana.FindProgramAddress([][]byte{}, c.programID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to derive IDL base address: %w", err)
+	}
+	return solana.CreateWithSeed(base, IDLAccountSeed, c.programID)
+}
+
+// FetchIDL loads and decompresses the program's IDL from its on-chain IDL account
+func (c *Client) FetchIDL(ctx context.Context) (*IDL, solana.PublicKey, error) {
+	idlAddress, err := c.DeriveIDLAddress()
+	if err != nil {
+		return nil, solana.PublicKey{}, err
+	}
+
+	accountInfo, err := c.getAccountInfo(ctx, idlAddress)
+	if err != nil {
+		return nil, solana.PublicKey{}, fmt.Errorf("failed to get IDL account info: %w", err)
+	}
+
+	if accountInfo.Value == nil {
+		return nil, solana.PublicKey{}, fmt.Errorf("IDL account not found")
+	}
+
+	idl, authority, err := DecodeIDLAccount(accountInfo.Value.Data.GetBinary())
+	if err != nil {
+		return nil, solana.PublicKey{}, err
+	}
+
+	return idl, authority, nil
+}
+
+// DecodeIDLAccount decodes the data of an Anchor IDL account, returning the
+// IDL and the authority allowed to update it
+func DecodeIDLAccount(data []byte) (*IDL, solana.PublicKey, error) {
+	if len(data) < idlAccountHeaderSize {
+		return nil, solana.PublicKey{}, fmt.Errorf("IDL account data too short: %d bytes", len(data))
+	}
+
+	authority := solana.PublicKeyFromBytes(data[AccountDiscriminatorSize : AccountDiscriminatorSize+32])
+	dataLen := binary.LittleEndian.Uint32(data[AccountDiscriminatorSize+32 : idlAccountHeaderSize])
+	if uint64(dataLen) > uint64(len(data)-idlAccountHeaderSize) {
+		return nil, solana.PublicKey{}, fmt.Errorf("IDL data length %d exceeds account size", dataLen)
+	}
+
+	reader, err := zlib.NewReader(bytes.NewReader(data[idlAccountHeaderSize : idlAccountHeaderSize+int(dataLen)]))
+	if err != nil {
+		return nil, solana.PublicKey{}, fmt.Errorf("failed to decompress IDL: %w", err)
+	}
+	defer reader.Close()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, solana.PublicKey{}, fmt.Errorf("failed to decompress IDL: %w", err)
+	}
+
+	idl, err := ParseIDL(raw)
+	if err != nil {
+		return nil, solana.PublicKey{}, err
+	}
+
+	return idl, authority, nil
+}
+
+// VerifyIDL fetches the program's on-chain IDL and compares it against the
+// SDK's compiled-in discriminators, account sizes and instruction layouts.
+// Callers should refuse to send instructions when the report is not Compatible.
+func (c *Client) VerifyIDL(ctx context.Context) (*IDLCompatibilityReport, error) {
+	idl, authority, err := c.FetchIDL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	idlAddress, err := c.DeriveIDLAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &IDLCompatibilityReport{
+		ProgramID:   c.programID,
+		IDLAddress:  idlAddress,
+		Authority:   authority,
+		ProgramName: idl.ProgramName(),
+		Mismatches:  compareIDL(idl, c.discriminators),
+	}
+	if idl.Metadata != nil {
+		report.ProgramVersion = idl.Metadata.Version
+	} else {
+		report.ProgramVersion = idl.Version
+	}
+
+	if idl.Address != "" && idl.Address != c.programID.String() {
+		report.Mismatches = append(report.Mismatches, IDLMismatch{
+			Kind:   "program",
+			Name:   report.ProgramName,
+			Detail: fmt.Sprintf("IDL declares address %s, client uses %s", idl.Address, c.programID),
+		})
+	}
+
+	return report, nil
+}
@@ -22,13 +22,35 @@ var (
 	MintConsumptionTokensDiscriminator = [8]byte{75, 241, 244, 71, 205, 59, 169, 126}
 )
 
+// Instruction arguments, serialized with borsh after the discriminator
+
+// MintEnergyTokensArgs holds the arguments of the mint_energy_tokens instruction
+type MintEnergyTokensArgs struct {
+	Amount     uint64     `borsh:"amount"`
+	EnergyType EnergyType `borsh:"energy_type"`
+}
+
+// ListTokensForSaleArgs holds the arguments of the list_tokens_for_sale instruction
+type ListTokensForSaleArgs struct {
+	Amount        uint64     `borsh:"amount"`
+	PriceLamports uint64     `borsh:"price_lamports"`
+	EnergyType    EnergyType `borsh:"energy_type"`
+}
+
+// BuyTokensArgs holds the arguments of the buy_tokens instruction
+type BuyTokensArgs struct {
+	ListingID solana.PublicKey `borsh:"listing_id"`
+}
+
+// MintConsumptionTokensArgs holds the arguments of the mint_consumption_tokens instruction
+type MintConsumptionTokensArgs struct {
+	Amount uint64 `borsh:"amount"`
+}
+
 // InitializeGrid creates an instruction to initialize a grid account
 func (c *Client) InitializeGrid(params GridAccountCreationParams) (solana.Instruction, error) {
-	if !ValidatePublicKey(params.Grid) {
-		return nil, fmt.Errorf("invalid grid public key")
-	}
-	if !ValidatePublicKey(params.Authority) {
-		return nil, fmt.Errorf("invalid authority public key")
+	if err := ValidateGridParams(params); err != nil {
+		return nil, err
 	}
 
 	gridAccountPDA, _, err := c.DeriveGridAccountPDA(params.Grid)
@@ -43,7 +65,7 @@ func (c *Client) InitializeGrid(params GridAccountCreationParams) (solana.Instru
 		{PublicKey: solana.SystemProgramID, IsWritable: false, IsSigner: false},
 	}
 
-	data := InitializeGridDiscriminator[:]
+	data := c.instructionData(InstructionInitializeGrid, nil)
 
 	return solana.NewInstruction(
 		c.programID,
@@ -54,11 +76,8 @@ func (c *Client) InitializeGrid(params GridAccountCreationParams) (solana.Instru
 
 // InitializeProducer creates an instruction to initialize a producer account
 func (c *Client) InitializeProducer(params ProducerAccountCreationParams) (solana.Instruction, error) {
-	if !ValidatePublicKey(params.Producer) {
-		return nil, fmt.Errorf("invalid producer public key")
-	}
-	if !ValidatePublicKey(params.Authority) {
-		return nil, fmt.Errorf("invalid authority public key")
+	if err := ValidateProducerParams(params); err != nil {
+		return nil, err
 	}
 
 	producerAccountPDA, _, err := c.DeriveProducerAccountPDA(params.Producer)
@@ -73,7 +92,7 @@ func (c *Client) InitializeProducer(params ProducerAccountCreationParams) (solan
 		{PublicKey: solana.SystemProgramID, IsWritable: false, IsSigner: false},
 	}
 
-	data := InitializeProducerDiscriminator[:]
+	data := c.instructionData(InstructionInitializeProducer, nil)
 
 	return solana.NewInstruction(
 		c.programID,
@@ -84,11 +103,8 @@ func (c *Client) InitializeProducer(params ProducerAccountCreationParams) (solan
 
 // InitializeConsumer creates an instruction to initialize a consumer account
 func (c *Client) InitializeConsumer(params ConsumerAccountCreationParams) (solana.Instruction, error) {
-	if !ValidatePublicKey(params.Consumer) {
-		return nil, fmt.Errorf("invalid consumer public key")
-	}
-	if !ValidatePublicKey(params.Authority) {
-		return nil, fmt.Errorf("invalid authority public key")
+	if err := ValidateConsumerParams(params); err != nil {
+		return nil, err
 	}
 
 	consumerAccountPDA, _, err := c.DeriveConsumerAccountPDA(params.Consumer)
@@ -103,7 +119,7 @@ func (c *Client) InitializeConsumer(params ConsumerAccountCreationParams) (solan
 		{PublicKey: solana.SystemProgramID, IsWritable: false, IsSigner: false},
 	}
 
-	data := InitializeConsumerDiscriminator[:]
+	data := c.instructionData(InstructionInitializeConsumer, nil)
 
 	return solana.NewInstruction(
 		c.programID,
@@ -114,20 +130,8 @@ func (c *Client) InitializeConsumer(params ConsumerAccountCreationParams) (solan
 
 // MintEnergyTokens creates an instruction to mint energy tokens
 func (c *Client) MintEnergyTokens(params MintRecordCreationParams) (solana.Instruction, error) {
-	if !ValidatePublicKey(params.Grid) {
-		return nil, fmt.Errorf("invalid grid public key")
-	}
-	if !ValidatePublicKey(params.Producer) {
-		return nil, fmt.Errorf("invalid producer public key")
-	}
-	if !ValidatePublicKey(params.GridAuthority) {
-		return nil, fmt.Errorf("invalid grid authority public key")
-	}
-	if !ValidateAmount(params.Amount) {
-		return nil, fmt.Errorf("invalid amount")
-	}
-	if !IsValidEnergyType(params.EnergyType) {
-		return nil, fmt.Errorf("invalid energy type")
+	if err := ValidateMintParams(params); err != nil {
+		return nil, err
 	}
 
 	producerAccountPDA, _, err := c.DeriveProducerAccountPDA(params.Producer)
@@ -155,12 +159,7 @@ func (c *Client) MintEnergyTokens(params MintRecordCreationParams) (solana.Instr
 	}
 
 	// Serialize instruction data
-	type InstructionData struct {
-		Amount     uint64 `borsh:"amount"`
-		EnergyType uint8  `borsh:"energy_type"`
-	}
-
-	instructionData := InstructionData{
+	instructionData := MintEnergyTokensArgs{
 		Amount:     params.Amount,
 		EnergyType: params.EnergyType,
 	}
@@ -170,7 +169,7 @@ func (c *Client) MintEnergyTokens(params MintRecordCreationParams) (solana.Instr
 		return nil, fmt.Errorf("failed to serialize instruction data: %w", err)
 	}
 
-	data := append(MintEnergyTokensDiscriminator[:], serializedData...)
+	data := c.instructionData(InstructionMintEnergyTokens, serializedData)
 
 	return solana.NewInstruction(
 		c.programID,
@@ -181,17 +180,8 @@ func (c *Client) MintEnergyTokens(params MintRecordCreationParams) (solana.Instr
 
 // ListTokensForSale creates an instruction to list tokens for sale
 func (c *Client) ListTokensForSale(params ListingAccountCreationParams) (solana.Instruction, error) {
-	if !ValidatePublicKey(params.Producer) {
-		return nil, fmt.Errorf("invalid producer public key")
-	}
-	if !ValidateAmount(params.Amount) {
-		return nil, fmt.Errorf("invalid amount")
-	}
-	if !ValidatePrice(params.PriceLamports) {
-		return nil, fmt.Errorf("invalid price")
-	}
-	if !IsValidEnergyType(params.EnergyType) {
-		return nil, fmt.Errorf("invalid energy type")
+	if err := ValidateListingParams(params); err != nil {
+		return nil, err
 	}
 
 	producerAccountPDA, _, err := c.DeriveProducerAccountPDA(params.Producer)
@@ -212,13 +202,7 @@ func (c *Client) ListTokensForSale(params ListingAccountCreationParams) (solana.
 	}
 
 	// Serialize instruction data
-	type InstructionData struct {
-		Amount        uint64 `borsh:"amount"`
-		PriceLamports uint64 `borsh:"price_lamports"`
-		EnergyType    uint8  `borsh:"energy_type"`
-	}
-
-	instructionData := InstructionData{
+	instructionData := ListTokensForSaleArgs{
 		Amount:        params.Amount,
 		PriceLamports: params.PriceLamports,
 		EnergyType:    params.EnergyType,
@@ -229,7 +213,7 @@ func (c *Client) ListTokensForSale(params ListingAccountCreationParams) (solana.
 		return nil, fmt.Errorf("failed to serialize instruction data: %w", err)
 	}
 
-	data := append(ListTokensForSaleDiscriminator[:], serializedData...)
+	data := c.instructionData(InstructionListTokensForSale, serializedData)
 
 	return solana.NewInstruction(
 		c.programID,
@@ -239,9 +223,10 @@ func (c *Client) ListTokensForSale(params ListingAccountCreationParams) (solana.
 }
 
 // CancelListing creates an instruction to cancel a listing
-func (c *Client) CancelListing(producer solana.PublicKey, amount, priceLamports uint64, energyType uint8) (solana.Instruction, error) {
-	if !ValidatePublicKey(producer) {
-		return nil, fmt.Errorf("invalid producer public key")
+func (c *Client) CancelListing(producer solana.PublicKey, amount, priceLamports uint64, energyType EnergyType) (solana.Instruction, error) {
+	seeds := ListingSeeds{Producer: producer, Amount: amount, PriceLamports: priceLamports, EnergyType: energyType}
+	if err := validateListingSeeds(InstructionCancelListing, seeds, nil); err != nil {
+		return nil, err
 	}
 
 	listingAccountPDA, _, err := c.DeriveListingAccountPDA(producer, amount, priceLamports, energyType)
@@ -249,6 +234,10 @@ func (c *Client) CancelListing(producer solana.PublicKey, amount, priceLamports
 		return nil, fmt.Errorf("failed to derive listing account PDA: %w", err)
 	}
 
+	return c.cancelListing(producer, listingAccountPDA)
+}
+
+func (c *Client) cancelListing(producer, listingAccountPDA solana.PublicKey) (solana.Instruction, error) {
 	producerAccountPDA, _, err := c.DeriveProducerAccountPDA(producer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to derive producer account PDA: %w", err)
@@ -260,7 +249,7 @@ func (c *Client) CancelListing(producer solana.PublicKey, amount, priceLamports
 		{PublicKey: producer, IsWritable: true, IsSigner: true},
 	}
 
-	data := CancelListingDiscriminator[:]
+	data := c.instructionData(InstructionCancelListing, nil)
 
 	return solana.NewInstruction(
 		c.programID,
@@ -270,12 +259,10 @@ func (c *Client) CancelListing(producer solana.PublicKey, amount, priceLamports
 }
 
 // BuyTokens creates an instruction to buy tokens from a listing
-func (c *Client) BuyTokens(buyer, producer solana.PublicKey, amount, priceLamports uint64, energyType uint8) (solana.Instruction, error) {
-	if !ValidatePublicKey(buyer) {
-		return nil, fmt.Errorf("invalid buyer public key")
-	}
-	if !ValidatePublicKey(producer) {
-		return nil, fmt.Errorf("invalid producer public key")
+func (c *Client) BuyTokens(buyer, producer solana.PublicKey, amount, priceLamports uint64, energyType EnergyType) (solana.Instruction, error) {
+	seeds := ListingSeeds{Producer: producer, Amount: amount, PriceLamports: priceLamports, EnergyType: energyType}
+	if err := validateListingSeeds(InstructionBuyTokens, seeds, &buyer); err != nil {
+		return nil, err
 	}
 
 	listingAccountPDA, _, err := c.DeriveListingAccountPDA(producer, amount, priceLamports, energyType)
@@ -283,6 +270,10 @@ func (c *Client) BuyTokens(buyer, producer solana.PublicKey, amount, priceLampor
 		return nil, fmt.Errorf("failed to derive listing account PDA: %w", err)
 	}
 
+	return c.buyTokens(buyer, producer, listingAccountPDA)
+}
+
+func (c *Client) buyTokens(buyer, producer, listingAccountPDA solana.PublicKey) (solana.Instruction, error) {
 	consumerAccountPDA, _, err := c.DeriveConsumerAccountPDA(buyer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to derive consumer account PDA: %w", err)
@@ -298,7 +289,7 @@ func (c *Client) BuyTokens(buyer, producer solana.PublicKey, amount, priceLampor
 
 	// Serialize instruction data (listing_id parameter)
 	listingIDBytes := listingAccountPDA.Bytes()
-	data := append(BuyTokensDiscriminator[:], listingIDBytes...)
+	data := c.instructionData(InstructionBuyTokens, listingIDBytes)
 
 	return solana.NewInstruction(
 		c.programID,
@@ -309,17 +300,13 @@ func (c *Client) BuyTokens(buyer, producer solana.PublicKey, amount, priceLampor
 
 // MintConsumptionTokens creates an instruction to mint consumption tokens
 func (c *Client) MintConsumptionTokens(consumer, grid, gridAuthority solana.PublicKey, amount uint64) (solana.Instruction, error) {
-	if !ValidatePublicKey(consumer) {
-		return nil, fmt.Errorf("invalid consumer public key")
-	}
-	if !ValidatePublicKey(grid) {
-		return nil, fmt.Errorf("invalid grid public key")
-	}
-	if !ValidatePublicKey(gridAuthority) {
-		return nil, fmt.Errorf("invalid grid authority public key")
-	}
-	if !ValidateAmount(amount) {
-		return nil, fmt.Errorf("invalid amount")
+	v := newValidator(InstructionMintConsumptionTokens)
+	v.publicKey("Consumer", consumer)
+	v.publicKey("Grid", grid)
+	v.publicKey("GridAuthority", gridAuthority)
+	v.amount("Amount", amount)
+	if err := v.err(); err != nil {
+		return nil, err
 	}
 
 	consumerAccountPDA, _, err := c.DeriveConsumerAccountPDA(consumer)
@@ -339,11 +326,7 @@ func (c *Client) MintConsumptionTokens(consumer, grid, gridAuthority solana.Publ
 	}
 
 	// Serialize instruction data
-	type InstructionData struct {
-		Amount uint64 `borsh:"amount"`
-	}
-
-	instructionData := InstructionData{
+	instructionData := MintConsumptionTokensArgs{
 		Amount: amount,
 	}
 
@@ -352,7 +335,7 @@ func (c *Client) MintConsumptionTokens(consumer, grid, gridAuthority solana.Publ
 		return nil, fmt.Errorf("failed to serialize instruction data: %w", err)
 	}
 
-	data := append(MintConsumptionTokensDiscriminator[:], serializedData...)
+	data := c.instructionData(InstructionMintConsumptionTokens, serializedData)
 
 	return solana.NewInstruction(
 		c.programID,
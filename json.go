@@ -0,0 +1,170 @@
+package zonnegosdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// JSON encodings of accounts and events render public keys as base58,
+// timestamps as RFC3339, energy types by name and lamport amounts alongside
+// an exact SOL string
+
+// mintRecordJSON is the JSON form of MintRecord
+type mintRecordJSON struct {
+	Grid       solana.PublicKey `json:"grid"`
+	Producer   solana.PublicKey `json:"producer"`
+	Amount     uint64           `json:"amount"`
+	EnergyType EnergyType       `json:"energy_type"`
+	Timestamp  time.Time        `json:"timestamp"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (m MintRecord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(mintRecordJSON{
+		Grid:       m.Grid,
+		Producer:   m.Producer,
+		Amount:     m.Amount,
+		EnergyType: m.EnergyType,
+		Timestamp:  m.GetTimestamp().UTC(),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (m *MintRecord) UnmarshalJSON(data []byte) error {
+	var v mintRecordJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*m = MintRecord{
+		Grid:       v.Grid,
+		Producer:   v.Producer,
+		Amount:     v.Amount,
+		EnergyType: v.EnergyType,
+		Timestamp:  v.Timestamp.Unix(),
+	}
+	return nil
+}
+
+// listingAccountJSON is the JSON form of ListingAccount
+type listingAccountJSON struct {
+	Producer      solana.PublicKey `json:"producer"`
+	Amount        uint64           `json:"amount"`
+	PriceLamports uint64           `json:"price_lamports"`
+	PriceSOL      string           `json:"price_sol"`
+	EnergyType    EnergyType       `json:"energy_type"`
+	IsActive      bool             `json:"is_active"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (l ListingAccount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(listingAccountJSON{
+		Producer:      l.Producer,
+		Amount:        l.Amount,
+		PriceLamports: l.PriceLamports,
+		PriceSOL:      FormatSOL(l.PriceLamports),
+		EnergyType:    l.EnergyType,
+		IsActive:      l.IsActive,
+		CreatedAt:     l.GetCreatedAt().UTC(),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *ListingAccount) UnmarshalJSON(data []byte) error {
+	var v listingAccountJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	price, err := lamportsFromJSON(v.PriceLamports, v.PriceSOL)
+	if err != nil {
+		return err
+	}
+
+	*l = ListingAccount{
+		Producer:      v.Producer,
+		Amount:        v.Amount,
+		PriceLamports: price,
+		EnergyType:    v.EnergyType,
+		IsActive:      v.IsActive,
+		CreatedAt:     v.CreatedAt.Unix(),
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (e TokensListedEvent) MarshalJSON() ([]byte, error) {
+	type event TokensListedEvent
+	return json.Marshal(struct {
+		event
+		PriceSOL string `json:"price_sol"`
+	}{event(e), FormatSOL(e.PriceLamports)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (e *TokensListedEvent) UnmarshalJSON(data []byte) error {
+	type event TokensListedEvent
+	v := struct {
+		*event
+		PriceSOL string `json:"price_sol"`
+	}{event: (*event)(e)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	price, err := lamportsFromJSON(e.PriceLamports, v.PriceSOL)
+	if err != nil {
+		return err
+	}
+	e.PriceLamports = price
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (e TokensPurchasedEvent) MarshalJSON() ([]byte, error) {
+	type event TokensPurchasedEvent
+	return json.Marshal(struct {
+		event
+		PriceSOL string `json:"price_sol"`
+	}{event(e), FormatSOL(e.PriceLamports)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (e *TokensPurchasedEvent) UnmarshalJSON(data []byte) error {
+	type event TokensPurchasedEvent
+	v := struct {
+		*event
+		PriceSOL string `json:"price_sol"`
+	}{event: (*event)(e)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	price, err := lamportsFromJSON(e.PriceLamports, v.PriceSOL)
+	if err != nil {
+		return err
+	}
+	e.PriceLamports = price
+	return nil
+}
+
+// lamportsFromJSON reconciles a lamport amount with its SOL string. Either
+// may be omitted; when both are present they must agree.
+func lamportsFromJSON(lamports uint64, sol string) (uint64, error) {
+	if sol == "" {
+		return lamports, nil
+	}
+
+	parsed, err := ParseSOL(sol)
+	if err != nil {
+		return 0, err
+	}
+	if lamports != 0 && lamports != parsed {
+		return 0, fmt.Errorf("price_lamports %d does not match price_sol %q", lamports, sol)
+	}
+	return parsed, nil
+}
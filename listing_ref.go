@@ -0,0 +1,185 @@
+package zonnegosdk
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// ListingRef identifies a listing account by its address, the seeds it was
+// derived from and its bump, so callers can persist a single value instead of
+// the four seed parameters
+type ListingRef struct {
+	Address solana.PublicKey `json:"address"`
+	Seeds   ListingSeeds     `json:"seeds"`
+	Bump    uint8            `json:"bump"`
+}
+
+// listingRefFields is the number of colon-separated fields in a ListingRef string
+const listingRefFields = 6
+
+// NewListingRef derives the listing account for the given seeds
+func (c *Client) NewListingRef(seeds ListingSeeds) (ListingRef, error) {
+	address, bump, err := c.DeriveListingAccountPDA(seeds.Producer, seeds.Amount, seeds.PriceLamports, seeds.EnergyType)
+	if err != nil {
+		return ListingRef{}, fmt.Errorf("failed to derive listing account PDA: %w", err)
+	}
+	return ListingRef{Address: address, Seeds: seeds, Bump: bump}, nil
+}
+
+// ListingRefFromAccount reconstructs the reference of a fetched listing account
+func (c *Client) ListingRefFromAccount(listing *ListingAccount) (ListingRef, error) {
+	return c.NewListingRef(ListingSeeds{
+		Producer:      listing.Producer,
+		Amount:        listing.Amount,
+		PriceLamports: listing.PriceLamports,
+		EnergyType:    listing.EnergyType,
+	})
+}
+
+// VerifyListingRef checks that the reference's address is the listing PDA of its seeds and bump
+func (c *Client) VerifyListingRef(ref ListingRef) error {
+	address, err := c.DeriveListingAccountPDAWithBump(ref.Seeds.Producer, ref.Seeds.Amount, ref.Seeds.PriceLamports, ref.Seeds.EnergyType, ref.Bump)
+	if err != nil {
+		return fmt.Errorf("invalid listing reference: %w", err)
+	}
+	if !address.Equals(ref.Address) {
+		return fmt.Errorf("invalid listing reference: seeds derive %s, not %s", address, ref.Address)
+	}
+	return nil
+}
+
+// String encodes the reference as address:producer:amount:price:energyType:bump
+func (r ListingRef) String() string {
+	return strings.Join([]string{
+		r.Address.String(),
+		r.Seeds.Producer.String(),
+		strconv.FormatUint(r.Seeds.Amount, 10),
+		strconv.FormatUint(r.Seeds.PriceLamports, 10),
+		strconv.FormatUint(uint64(r.Seeds.EnergyType), 10),
+		strconv.FormatUint(uint64(r.Bump), 10),
+	}, ":")
+}
+
+// ParseListingRef parses a reference produced by ListingRef.String
+func ParseListingRef(s string) (ListingRef, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != listingRefFields {
+		return ListingRef{}, fmt.Errorf("invalid listing reference %q: expected %d fields", s, listingRefFields)
+	}
+
+	address, err := solana.PublicKeyFromBase58(parts[0])
+	if err != nil {
+		return ListingRef{}, fmt.Errorf("invalid listing address: %w", err)
+	}
+	producer, err := solana.PublicKeyFromBase58(parts[1])
+	if err != nil {
+		return ListingRef{}, fmt.Errorf("invalid listing producer: %w", err)
+	}
+	amount, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return ListingRef{}, fmt.Errorf("invalid listing amount: %w", err)
+	}
+	price, err := strconv.ParseUint(parts[3], 10, 64)
+	if err != nil {
+		return ListingRef{}, fmt.Errorf("invalid listing price: %w", err)
+	}
+	energyType, err := strconv.ParseUint(parts[4], 10, 8)
+	if err != nil {
+		return ListingRef{}, fmt.Errorf("invalid listing energy type: %w", err)
+	}
+	bump, err := strconv.ParseUint(parts[5], 10, 8)
+	if err != nil {
+		return ListingRef{}, fmt.Errorf("invalid listing bump: %w", err)
+	}
+
+	return ListingRef{
+		Address: address,
+		Seeds: ListingSeeds{
+			Producer:      producer,
+			Amount:        amount,
+			PriceLamports: price,
+			EnergyType:    EnergyType(energyType),
+		},
+		Bump: uint8(bump),
+	}, nil
+}
+
+// CreateListing creates an instruction to list tokens for sale and returns
+// the reference of the listing it creates
+func (c *Client) CreateListing(params ListingAccountCreationParams) (solana.Instruction, ListingRef, error) {
+	instruction, err := c.ListTokensForSale(params)
+	if err != nil {
+		return nil, ListingRef{}, err
+	}
+
+	ref, err := c.NewListingRef(ListingSeeds{
+		Producer:      params.Producer,
+		Amount:        params.Amount,
+		PriceLamports: params.PriceLamports,
+		EnergyType:    params.EnergyType,
+	})
+	if err != nil {
+		return nil, ListingRef{}, err
+	}
+
+	return instruction, ref, nil
+}
+
+// BuyTokensFromListing creates an instruction to buy tokens from a referenced listing
+func (c *Client) BuyTokensFromListing(buyer solana.PublicKey, ref ListingRef) (solana.Instruction, error) {
+	if err := validateListingSeeds(InstructionBuyTokens, ref.Seeds, &buyer); err != nil {
+		return nil, err
+	}
+
+	address, err := c.listingRefAddress(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.buyTokens(buyer, ref.Seeds.Producer, address)
+}
+
+// CancelListingByRef creates an instruction to cancel a referenced listing
+func (c *Client) CancelListingByRef(ref ListingRef) (solana.Instruction, error) {
+	if err := validateListingSeeds(InstructionCancelListing, ref.Seeds, nil); err != nil {
+		return nil, err
+	}
+
+	address, err := c.listingRefAddress(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.cancelListing(ref.Seeds.Producer, address)
+}
+
+// GetListingAccountByRef fetches a referenced listing account
+func (c *Client) GetListingAccountByRef(ctx context.Context, ref ListingRef) (*ListingAccount, error) {
+	address, err := c.listingRefAddress(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getListingAccount(ctx, address)
+}
+
+// listingRefAddress returns the reference's address after verifying it
+// against the seeds and bump, deriving it from the seeds when the reference
+// carries none
+func (c *Client) listingRefAddress(ref ListingRef) (solana.PublicKey, error) {
+	if !ref.Address.IsZero() {
+		if err := c.VerifyListingRef(ref); err != nil {
+			return solana.PublicKey{}, err
+		}
+		return ref.Address, nil
+	}
+	derived, err := c.NewListingRef(ref.Seeds)
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	return derived.Address, nil
+}
@@ -0,0 +1,69 @@
+package zonnegosdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/near/borsh-go"
+)
+
+// ListingFilter narrows the listings returned by GetListings
+type ListingFilter struct {
+	// Producer, when set, returns only listings of this producer
+	Producer *solana.PublicKey
+	// ActiveOnly skips listings that have been bought or cancelled
+	ActiveOnly bool
+}
+
+// FetchedListing is a listing account together with its reference
+type FetchedListing struct {
+	Ref     ListingRef     `json:"ref"`
+	Account ListingAccount `json:"account"`
+}
+
+// GetListings fetches every listing account of the program matching the filter
+func (c *Client) GetListings(ctx context.Context, filter ListingFilter) ([]FetchedListing, error) {
+	discriminator := ComputeAccountDiscriminator("ListingAccount")
+	filters := []rpc.RPCFilter{
+		{DataSize: ListingAccountSize},
+		{Memcmp: &rpc.RPCFilterMemcmp{Offset: 0, Bytes: discriminator[:]}},
+	}
+	if filter.Producer != nil {
+		filters = append(filters, rpc.RPCFilter{
+			Memcmp: &rpc.RPCFilterMemcmp{Offset: AccountDiscriminatorSize, Bytes: filter.Producer.Bytes()},
+		})
+	}
+
+	accounts, err := c.rpcClient.GetProgramAccountsWithOpts(ctx, c.programID, &rpc.GetProgramAccountsOpts{
+		Encoding: solana.EncodingBase64,
+		Filters:  filters,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listing accounts: %w", err)
+	}
+
+	var listings []FetchedListing
+	for _, account := range accounts {
+		var listing ListingAccount
+		if err := borsh.Deserialize(&listing, account.Account.Data.GetBinary()[AccountDiscriminatorSize:]); err != nil {
+			return nil, fmt.Errorf("failed to deserialize listing account %s: %w", account.Pubkey, err)
+		}
+		if filter.ActiveOnly && !listing.IsActive {
+			continue
+		}
+
+		ref, err := c.ListingRefFromAccount(&listing)
+		if err != nil {
+			return nil, err
+		}
+		if !ref.Address.Equals(account.Pubkey) {
+			return nil, fmt.Errorf("listing account %s does not match its seeds", account.Pubkey)
+		}
+
+		listings = append(listings, FetchedListing{Ref: ref, Account: listing})
+	}
+
+	return listings, nil
+}
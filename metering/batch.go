@@ -0,0 +1,80 @@
+package metering
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/gagliardetto/solana-go"
+)
+
+// Batch is the new readings of one producer and energy type, minted together
+type Batch struct {
+	Producer   solana.PublicKey      `json:"producer"`
+	EnergyType zonnegosdk.EnergyType `json:"energy_type"`
+	Readings   []Reading             `json:"readings"`
+	// Energy is the readings' total plus the carry of earlier batches
+	Energy zonnegosdk.Energy `json:"energy"`
+	// Tokens is the whole number of tokens to mint; Carry is the remainder
+	// left for the next batch
+	Tokens uint64            `json:"tokens"`
+	Carry  zonnegosdk.Energy `json:"carry"`
+}
+
+// Aggregate groups the readings not yet minted according to the checkpoint
+// into one batch per producer and energy type, adding the energy carried
+// over from earlier batches. It returns the batches and the readings skipped
+// as duplicates: those ending at or before their meter's checkpoint, and
+// repeats of a reading in the input.
+//
+// Readings of a meter must arrive in time order: one that overlaps its
+// meter's checkpoint or another reading is an error, as is a meter reporting
+// more than one energy type.
+func Aggregate(checkpoint *Checkpoint, readings []Reading) ([]Batch, []Reading, error) {
+	fresh, duplicates, err := filterReadings(checkpoint.Meters, readings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	batches := make(map[string]*Batch)
+	meterTypes := make(map[string]zonnegosdk.EnergyType)
+	for _, reading := range fresh {
+		key := reading.MeterKey()
+		if energyType, ok := meterTypes[key]; ok && energyType != reading.EnergyType {
+			return nil, nil, fmt.Errorf("meter %s reports both %s and %s energy", key, energyType, reading.EnergyType)
+		}
+		meterTypes[key] = reading.EnergyType
+
+		stream := streamKey(reading.Wallet, reading.EnergyType)
+		batch, ok := batches[stream]
+		if !ok {
+			batch = &Batch{
+				Producer:   reading.Wallet,
+				EnergyType: reading.EnergyType,
+				Energy:     checkpoint.Carry[stream],
+			}
+			batches[stream] = batch
+		}
+		if batch.Energy+reading.Energy < batch.Energy {
+			return nil, nil, fmt.Errorf("energy of producer %s overflows", reading.Wallet)
+		}
+		batch.Energy += reading.Energy
+		batch.Readings = append(batch.Readings, reading)
+	}
+
+	result := make([]Batch, 0, len(batches))
+	for _, batch := range batches {
+		batch.Tokens = uint64(batch.Energy / zonnegosdk.TokenUnit)
+		batch.Carry = batch.Energy % zonnegosdk.TokenUnit
+		result = append(result, *batch)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if c := bytes.Compare(result[i].Producer[:], result[j].Producer[:]); c != 0 {
+			return c < 0
+		}
+		return result[i].EnergyType < result[j].EnergyType
+	})
+
+	return result, duplicates, nil
+}
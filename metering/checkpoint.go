@@ -0,0 +1,185 @@
+package metering
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/gagliardetto/solana-go"
+)
+
+// Checkpoint is the ingestion state that survives restarts
+type Checkpoint struct {
+	// Meters maps each wallet's meter to the end of its last ingested
+	// reading. Readings ending at or before it are duplicates.
+	Meters map[string]time.Time `json:"meters"`
+	// Carry maps each producer and energy type to the energy ingested but
+	// not minted because it is less than one token
+	Carry map[string]zonnegosdk.Energy `json:"carry"`
+	// Pending is the mint sent but not yet seen on chain
+	Pending *PendingMint `json:"pending,omitempty"`
+	// Consumers holds the consumption bridge's state of each consumer
+	Consumers map[string]*ConsumerCheckpoint `json:"consumers,omitempty"`
+}
+
+// PendingMint is a mint transaction whose outcome is not known yet. Its
+// meter and carry entries are applied to the checkpoint once it is
+// finalized, and dropped once its blockhash expires without it landing.
+type PendingMint struct {
+	Signature            solana.Signature             `json:"signature"`
+	LastValidBlockHeight uint64                       `json:"last_valid_block_height"`
+	Mints                []zonnegosdk.MintRecordSeeds `json:"mints"`
+	Meters               map[string]time.Time         `json:"meters"`
+	Carry                map[string]zonnegosdk.Energy `json:"carry"`
+}
+
+// ConsumerCheckpoint is the consumption bridge's state of one consumer
+type ConsumerCheckpoint struct {
+	// Baseline is the consumer's on-chain consumption, in tokens, when the
+	// bridge first saw the consumer
+	Baseline uint64 `json:"baseline"`
+	// Metered is the consumption metered since then
+	Metered zonnegosdk.Energy `json:"metered"`
+	// Pending is the consumption mint sent but not yet finalized
+	Pending *PendingConsumptionMint `json:"pending,omitempty"`
+}
+
+// PendingConsumptionMint is a MintConsumptionTokens transaction whose outcome
+// is not known yet
+type PendingConsumptionMint struct {
+	Signature            solana.Signature `json:"signature"`
+	LastValidBlockHeight uint64           `json:"last_valid_block_height"`
+	Amount               uint64           `json:"amount"`
+}
+
+// NewCheckpoint returns an empty checkpoint
+func NewCheckpoint() *Checkpoint {
+	return &Checkpoint{
+		Meters:    make(map[string]time.Time),
+		Carry:     make(map[string]zonnegosdk.Energy),
+		Consumers: make(map[string]*ConsumerCheckpoint),
+	}
+}
+
+// apply records the pending mint's meter and carry entries and clears it
+func (c *Checkpoint) apply(pending *PendingMint) {
+	for key, end := range pending.Meters {
+		c.Meters[key] = end
+	}
+	for key, carry := range pending.Carry {
+		c.Carry[key] = carry
+	}
+	c.Pending = nil
+}
+
+// clone returns a copy of the checkpoint whose meter and carry entries can
+// be changed without affecting it
+func (c *Checkpoint) clone() *Checkpoint {
+	clone := &Checkpoint{
+		Meters:    make(map[string]time.Time, len(c.Meters)),
+		Carry:     make(map[string]zonnegosdk.Energy, len(c.Carry)),
+		Pending:   c.Pending,
+		Consumers: c.Consumers,
+	}
+	for key, end := range c.Meters {
+		clone.Meters[key] = end
+	}
+	for key, carry := range c.Carry {
+		clone.Carry[key] = carry
+	}
+	return clone
+}
+
+// CheckpointStore persists the checkpoint. Save must be atomic: after a
+// crash, Load returns either the previous or the new checkpoint.
+type CheckpointStore interface {
+	Load() (*Checkpoint, error)
+	Save(checkpoint *Checkpoint) error
+}
+
+// FileCheckpointStore stores the checkpoint as a JSON file
+type FileCheckpointStore struct {
+	Path string
+}
+
+// NewFileCheckpointStore creates a store for the checkpoint file at path
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{Path: path}
+}
+
+// Load reads the checkpoint, returning an empty one if the file does not exist
+func (s *FileCheckpointStore) Load() (*Checkpoint, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewCheckpoint(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	checkpoint := NewCheckpoint()
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", s.Path, err)
+	}
+	if checkpoint.Meters == nil {
+		checkpoint.Meters = make(map[string]time.Time)
+	}
+	if checkpoint.Carry == nil {
+		checkpoint.Carry = make(map[string]zonnegosdk.Energy)
+	}
+	if checkpoint.Consumers == nil {
+		checkpoint.Consumers = make(map[string]*ConsumerCheckpoint)
+	}
+	return checkpoint, nil
+}
+
+// Save writes the checkpoint atomically with WriteFileAtomic
+func (s *FileCheckpointStore) Save(checkpoint *Checkpoint) error {
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	if err := WriteFileAtomic(s.Path, data); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// WriteFileAtomic writes data to a temporary file next to path, syncs it and
+// renames it over path, so after a crash path holds either the old or the new
+// contents
+func WriteFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	file, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", file.Name(), err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to sync %s: %w", file.Name(), err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file.Name(), err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	// Persist the rename itself
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
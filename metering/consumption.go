@@ -0,0 +1,324 @@
+package metering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// The consumption bridge mints consumption tokens for metered consumption
+// that purchases do not cover. A consumer's on-chain consumption counter
+// grows with both BuyTokens and MintConsumptionTokens, so the bridge keeps
+// each consumer's metered total since a baseline of the counter and mints
+// the difference:
+//
+//	amount = whole tokens of metered - (on-chain consumption - baseline)
+//
+// Because the amount is recomputed from the totals, a mint that never landed
+// is simply included in the next one. Only a mint whose outcome is unknown
+// needs tracking, as pending, so it is not minted again while in flight.
+
+// ConsumptionConfig configures a ConsumptionBridge
+type ConsumptionConfig struct {
+	Grid solana.PublicKey
+	// GridAuthority signs the mints and pays the transaction fees
+	GridAuthority solana.PrivateKey
+	// ConsumerAuthority initializes missing consumer accounts and pays their
+	// rent. Defaults to GridAuthority.
+	ConsumerAuthority solana.PrivateKey
+	Checkpoint        CheckpointStore
+}
+
+// ConsumptionBridge mints consumption tokens for consumer meter readings. It
+// is not safe for concurrent use, and only one bridge may use a checkpoint at
+// a time.
+type ConsumptionBridge struct {
+	client     *zonnegosdk.Client
+	config     ConsumptionConfig
+	checkpoint *Checkpoint
+}
+
+// ConsumptionMint is the consumption mint planned or sent for one consumer
+type ConsumptionMint struct {
+	Consumer solana.PublicKey `json:"consumer"`
+	Readings []Reading        `json:"readings"`
+	// Metered is the consumption metered since the baseline, including the
+	// readings
+	Metered zonnegosdk.Energy `json:"metered"`
+	// Covered is the on-chain consumption added since the baseline, in
+	// tokens: purchases and earlier consumption mints
+	Covered uint64 `json:"covered"`
+	// Amount is the tokens to mint: the whole tokens of Metered not Covered
+	Amount              uint64 `json:"amount"`
+	InitializesConsumer bool   `json:"initializes_consumer"`
+	// Signature is the mint transaction, nil for a plan or when Amount is 0
+	Signature *solana.Signature `json:"signature,omitempty"`
+}
+
+// ConsumptionReport is the outcome of Plan or Ingest
+type ConsumptionReport struct {
+	// Mints are the consumers processed, in order. When Ingest fails, the
+	// consumers before the failure are reported.
+	Mints []ConsumptionMint `json:"mints"`
+	// Duplicates are the readings skipped because they were already ingested
+	Duplicates []Reading `json:"duplicates,omitempty"`
+}
+
+// NewConsumptionBridge creates a consumption bridge and loads its checkpoint
+func NewConsumptionBridge(client *zonnegosdk.Client, config ConsumptionConfig) (*ConsumptionBridge, error) {
+	if config.Grid.IsZero() {
+		return nil, fmt.Errorf("consumption bridge has no grid")
+	}
+	if err := checkPrivateKey("grid authority", config.GridAuthority); err != nil {
+		return nil, err
+	}
+	if config.ConsumerAuthority == nil {
+		config.ConsumerAuthority = config.GridAuthority
+	}
+	if err := checkPrivateKey("consumer authority", config.ConsumerAuthority); err != nil {
+		return nil, err
+	}
+	if config.Checkpoint == nil {
+		return nil, fmt.Errorf("consumption bridge has no checkpoint store")
+	}
+
+	checkpoint, err := config.Checkpoint.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConsumptionBridge{client: client, config: config, checkpoint: checkpoint}, nil
+}
+
+// Checkpoint returns the bridge's current checkpoint
+func (b *ConsumptionBridge) Checkpoint() *Checkpoint {
+	return b.checkpoint
+}
+
+// Plan computes the mints Ingest would send for the readings, without
+// sending them or changing the checkpoint
+func (b *ConsumptionBridge) Plan(ctx context.Context, readings []Reading) (*ConsumptionReport, error) {
+	return b.run(ctx, readings, false)
+}
+
+// Ingest records the readings not ingested yet and mints the consumption
+// they add beyond what each consumer's purchases cover, one transaction per
+// consumer, saving the checkpoint after each. Readings may be passed again
+// after a failure; those already ingested are skipped.
+func (b *ConsumptionBridge) Ingest(ctx context.Context, readings []Reading) (*ConsumptionReport, error) {
+	return b.run(ctx, readings, true)
+}
+
+// run plans the mint of every consumer with new readings or a pending mint,
+// and sends them if send is set
+func (b *ConsumptionBridge) run(ctx context.Context, readings []Reading, send bool) (*ConsumptionReport, error) {
+	report := &ConsumptionReport{}
+
+	fresh, duplicates, err := filterReadings(b.checkpoint.Meters, readings)
+	if err != nil {
+		return report, err
+	}
+	report.Duplicates = duplicates
+
+	byConsumer := make(map[string][]Reading)
+	for _, reading := range fresh {
+		key := reading.Wallet.String()
+		byConsumer[key] = append(byConsumer[key], reading)
+	}
+	for key, state := range b.checkpoint.Consumers {
+		if state.Pending != nil {
+			if _, ok := byConsumer[key]; !ok {
+				byConsumer[key] = nil
+			}
+		}
+	}
+	keys := make([]string, 0, len(byConsumer))
+	for key := range byConsumer {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		consumer := solana.MustPublicKeyFromBase58(key)
+
+		if state := b.checkpoint.Consumers[key]; state != nil && state.Pending != nil {
+			resolved, err := b.resolve(ctx, state.Pending)
+			if err != nil {
+				return report, err
+			}
+			if !resolved {
+				return report, fmt.Errorf("consumer %s: transaction %s: %w", consumer, state.Pending.Signature, ErrMintPending)
+			}
+			if send {
+				state.Pending = nil
+				if err := b.config.Checkpoint.Save(b.checkpoint); err != nil {
+					return report, err
+				}
+			}
+		}
+
+		mint, next, err := b.plan(ctx, consumer, byConsumer[key])
+		if err != nil {
+			return report, fmt.Errorf("failed to plan consumption mint for %s: %w", consumer, err)
+		}
+		if len(mint.Readings) == 0 && mint.Amount == 0 {
+			continue
+		}
+		if send {
+			if err := b.mint(ctx, mint, next); err != nil {
+				return report, fmt.Errorf("failed to mint consumption for %s: %w", consumer, err)
+			}
+		}
+		report.Mints = append(report.Mints, *mint)
+	}
+
+	return report, nil
+}
+
+// plan computes the consumer's mint for the readings and the consumer's
+// checkpoint once they are ingested
+func (b *ConsumptionBridge) plan(ctx context.Context, consumer solana.PublicKey, readings []Reading) (*ConsumptionMint, *ConsumerCheckpoint, error) {
+	var consumption uint64
+	exists := true
+	account, err := b.client.GetConsumerAccount(ctx, consumer)
+	switch {
+	case errors.Is(err, rpc.ErrNotFound):
+		exists = false
+	case err != nil:
+		return nil, nil, err
+	default:
+		consumption = account.Consumption
+	}
+
+	next := &ConsumerCheckpoint{Baseline: consumption}
+	if state := b.checkpoint.Consumers[consumer.String()]; state != nil {
+		next.Baseline = state.Baseline
+		next.Metered = state.Metered
+	}
+	if consumption < next.Baseline {
+		return nil, nil, fmt.Errorf("on-chain consumption %d is below the baseline %d", consumption, next.Baseline)
+	}
+
+	for _, reading := range readings {
+		if next.Metered+reading.Energy < next.Metered {
+			return nil, nil, fmt.Errorf("metered consumption overflows")
+		}
+		next.Metered += reading.Energy
+	}
+
+	mint := &ConsumptionMint{
+		Consumer: consumer,
+		Readings: readings,
+		Metered:  next.Metered,
+		Covered:  consumption - next.Baseline,
+	}
+	if tokens := uint64(next.Metered / zonnegosdk.TokenUnit); tokens > mint.Covered {
+		mint.Amount = tokens - mint.Covered
+		mint.InitializesConsumer = !exists
+	}
+
+	return mint, next, nil
+}
+
+// mint records the consumer's readings and sends the mint, tracking it as
+// pending until it is finalized
+func (b *ConsumptionBridge) mint(ctx context.Context, mint *ConsumptionMint, next *ConsumerCheckpoint) error {
+	meters := lastEnds(mint.Readings)
+	commit := func() error {
+		for key, end := range meters {
+			b.checkpoint.Meters[key] = end
+		}
+		b.checkpoint.Consumers[mint.Consumer.String()] = next
+		return b.config.Checkpoint.Save(b.checkpoint)
+	}
+	if mint.Amount == 0 {
+		return commit()
+	}
+
+	gridAuthority, consumerAuthority := b.config.GridAuthority, b.config.ConsumerAuthority
+	var instructions []solana.Instruction
+	if mint.InitializesConsumer {
+		instruction, err := b.client.InitializeConsumer(zonnegosdk.ConsumerAccountCreationParams{Consumer: mint.Consumer, Authority: consumerAuthority.PublicKey()})
+		if err != nil {
+			return err
+		}
+		instructions = append(instructions, instruction)
+	}
+	instruction, err := b.client.MintConsumptionTokens(mint.Consumer, b.config.Grid, gridAuthority.PublicKey(), mint.Amount)
+	if err != nil {
+		return err
+	}
+	instructions = append(instructions, instruction)
+
+	transaction, lastValidBlockHeight, err := b.client.BuildUnsignedTransaction(ctx, gridAuthority.PublicKey(), instructions...)
+	if err != nil {
+		return err
+	}
+	signers := []solana.PrivateKey{gridAuthority}
+	if mint.InitializesConsumer && !consumerAuthority.PublicKey().Equals(gridAuthority.PublicKey()) {
+		signers = append(signers, consumerAuthority)
+	}
+	if err := zonnegosdk.PartialSignTransaction(transaction, signers...); err != nil {
+		return err
+	}
+
+	// Record the readings and the mint before sending it, so a crash after
+	// this point waits for its outcome instead of minting it again
+	next.Pending = &PendingConsumptionMint{
+		Signature:            transaction.Signatures[0],
+		LastValidBlockHeight: lastValidBlockHeight,
+		Amount:               mint.Amount,
+	}
+	if err := commit(); err != nil {
+		return err
+	}
+
+	if _, err := b.client.SubmitSignedTransaction(ctx, transaction); err != nil {
+		return err
+	}
+	// A confirmation timeout leaves the outcome to resolve below
+	if err := b.client.ConfirmTransaction(ctx, next.Pending.Signature); err != nil && !errors.Is(err, zonnegosdk.ErrConfirmationTimeout) {
+		return err
+	}
+
+	resolved, err := b.resolve(ctx, next.Pending)
+	if err != nil {
+		return err
+	}
+	if !resolved {
+		return fmt.Errorf("transaction %s: %w", next.Pending.Signature, ErrMintPending)
+	}
+	signature := next.Pending.Signature
+	next.Pending = nil
+	if err := b.config.Checkpoint.Save(b.checkpoint); err != nil {
+		return err
+	}
+
+	mint.Signature = &signature
+	return nil
+}
+
+// resolve reports whether the pending mint's outcome is final: it was
+// finalized, successfully or not, or its blockhash expired without it landing
+func (b *ConsumptionBridge) resolve(ctx context.Context, pending *PendingConsumptionMint) (bool, error) {
+	rpcClient := b.client.GetRPCClient()
+
+	statuses, err := rpcClient.GetSignatureStatuses(ctx, true, pending.Signature)
+	if err != nil {
+		return false, fmt.Errorf("failed to get signature status: %w", err)
+	}
+	if len(statuses.Value) > 0 && statuses.Value[0] != nil {
+		return statuses.Value[0].ConfirmationStatus == rpc.ConfirmationStatusFinalized, nil
+	}
+
+	height, err := rpcClient.GetBlockHeight(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return false, fmt.Errorf("failed to get block height: %w", err)
+	}
+	return height > pending.LastValidBlockHeight, nil
+}
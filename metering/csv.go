@@ -0,0 +1,134 @@
+package metering
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/gagliardetto/solana-go"
+)
+
+// CSV columns. start, end and energy are required; the others default to
+// the Source passed to ParseCSV. The wallet column may also be named
+// producer or consumer.
+const (
+	ColumnWallet     = "wallet"
+	ColumnMeterID    = "meter_id"
+	ColumnStart      = "start"
+	ColumnEnd        = "end"
+	ColumnEnergy     = "energy"
+	ColumnEnergyType = "energy_type"
+)
+
+// ParseCSV reads interval readings from CSV with a header row naming the
+// columns, in any order. start and end are RFC 3339 timestamps and energy is
+// a quantity such as "1.5kWh" or a plain number of Wh.
+//
+//	meter_id,start,end,energy
+//	roof-1,2024-06-01T10:00:00Z,2024-06-01T10:15:00Z,1.25kWh
+func ParseCSV(r io.Reader, source Source) ([]Reading, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "producer" || name == "consumer" {
+			name = ColumnWallet
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{ColumnStart, ColumnEnd, ColumnEnergy} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV header has no %q column", name)
+		}
+	}
+
+	var readings []Reading
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		reading, err := parseCSVRecord(record, columns, source)
+		if err != nil {
+			return nil, fmt.Errorf("CSV line %d: %w", line, err)
+		}
+		readings = append(readings, reading)
+	}
+
+	return readings, nil
+}
+
+// parseCSVRecord converts one CSV record to a reading
+func parseCSVRecord(record []string, columns map[string]int, source Source) (Reading, error) {
+	field := func(name string) (string, bool) {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return "", false
+		}
+		return strings.TrimSpace(record[i]), true
+	}
+
+	reading := Reading{
+		Wallet:     source.Wallet,
+		MeterID:    source.MeterID,
+		EnergyType: source.EnergyType,
+	}
+
+	if value, ok := field(ColumnWallet); ok && value != "" {
+		wallet, err := solana.PublicKeyFromBase58(value)
+		if err != nil {
+			return Reading{}, fmt.Errorf("invalid wallet %q: %w", value, err)
+		}
+		reading.Wallet = wallet
+	}
+	if value, ok := field(ColumnMeterID); ok && value != "" {
+		reading.MeterID = value
+	}
+	if value, ok := field(ColumnEnergyType); ok && value != "" {
+		energyType, err := zonnegosdk.ParseEnergyTypeStrict(value)
+		if err != nil {
+			return Reading{}, err
+		}
+		reading.EnergyType = energyType
+	}
+
+	var err error
+	start, _ := field(ColumnStart)
+	if reading.Start, err = time.Parse(time.RFC3339, start); err != nil {
+		return Reading{}, fmt.Errorf("invalid start %q: %w", start, err)
+	}
+	end, _ := field(ColumnEnd)
+	if reading.End, err = time.Parse(time.RFC3339, end); err != nil {
+		return Reading{}, fmt.Errorf("invalid end %q: %w", end, err)
+	}
+	energy, _ := field(ColumnEnergy)
+	if reading.Energy, err = parseEnergy(energy); err != nil {
+		return Reading{}, err
+	}
+
+	return reading, reading.Validate()
+}
+
+// parseEnergy parses an energy quantity with a unit, or a plain number of Wh
+func parseEnergy(s string) (zonnegosdk.Energy, error) {
+	if wh, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return zonnegosdk.Energy(wh), nil
+	}
+	return zonnegosdk.ParseEnergy(s)
+}
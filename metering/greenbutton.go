@@ -0,0 +1,144 @@
+package metering
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+)
+
+// ESPI codes used by Green Button data
+const (
+	// UOMWattHour is the ESPI unit of measure code for Wh
+	UOMWattHour = 72
+	// FlowForward is energy delivered to the customer, i.e. consumption
+	FlowForward = 1
+	// FlowReverse is energy received from the customer, i.e. generation
+	FlowReverse = 19
+)
+
+// espiReadingType is the part of an ESPI ReadingType used to scale values
+type espiReadingType struct {
+	FlowDirection        int `xml:"flowDirection"`
+	PowerOfTenMultiplier int `xml:"powerOfTenMultiplier"`
+	UOM                  int `xml:"uom"`
+}
+
+// espiIntervalBlock is an ESPI IntervalBlock
+type espiIntervalBlock struct {
+	Readings []struct {
+		TimePeriod struct {
+			Duration int64 `xml:"duration"`
+			Start    int64 `xml:"start"`
+		} `xml:"timePeriod"`
+		Value int64 `xml:"value"`
+	} `xml:"IntervalReading"`
+}
+
+// ParseGreenButton reads generation interval readings from a Green Button
+// (NAESB ESPI) XML document, either an Atom feed or a bare IntervalBlock. The
+// wallet, meter ID and energy type come from the source.
+//
+// Each IntervalBlock is scaled by the ReadingType preceding it in the
+// document. Blocks of forward flow readings are consumption and are skipped;
+// reverse flow and unspecified blocks are generation. Values must be in Wh
+// and scale to a whole number of Wh.
+func ParseGreenButton(r io.Reader, source Source) ([]Reading, error) {
+	return parseGreenButton(r, source, FlowForward)
+}
+
+// ParseGreenButtonConsumption reads consumption interval readings from a
+// Green Button document as ParseGreenButton does, skipping the reverse flow
+// (generation) blocks instead
+func ParseGreenButtonConsumption(r io.Reader, source Source) ([]Reading, error) {
+	return parseGreenButton(r, source, FlowReverse)
+}
+
+// parseGreenButton reads the interval readings of a Green Button document,
+// skipping blocks with the given flow direction
+func parseGreenButton(r io.Reader, source Source, skipFlow int) ([]Reading, error) {
+	readingType := espiReadingType{UOM: UOMWattHour}
+	var readings []Reading
+
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Green Button XML: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "ReadingType":
+			readingType = espiReadingType{UOM: UOMWattHour}
+			if err := decoder.DecodeElement(&readingType, &start); err != nil {
+				return nil, fmt.Errorf("failed to decode ReadingType: %w", err)
+			}
+		case "IntervalBlock":
+			var block espiIntervalBlock
+			if err := decoder.DecodeElement(&block, &start); err != nil {
+				return nil, fmt.Errorf("failed to decode IntervalBlock: %w", err)
+			}
+			if readingType.FlowDirection == skipFlow {
+				continue
+			}
+			if readingType.UOM != UOMWattHour {
+				return nil, fmt.Errorf("unsupported unit of measure %d: only Wh (%d) is energy", readingType.UOM, UOMWattHour)
+			}
+
+			for _, interval := range block.Readings {
+				energy, err := scaleESPIValue(interval.Value, readingType.PowerOfTenMultiplier)
+				if err != nil {
+					return nil, fmt.Errorf("interval at %d: %w", interval.TimePeriod.Start, err)
+				}
+				reading := Reading{
+					Wallet:     source.Wallet,
+					MeterID:    source.MeterID,
+					Start:      time.Unix(interval.TimePeriod.Start, 0).UTC(),
+					End:        time.Unix(interval.TimePeriod.Start+interval.TimePeriod.Duration, 0).UTC(),
+					Energy:     energy,
+					EnergyType: source.EnergyType,
+				}
+				if err := reading.Validate(); err != nil {
+					return nil, err
+				}
+				readings = append(readings, reading)
+			}
+		}
+	}
+
+	return readings, nil
+}
+
+// scaleESPIValue converts an ESPI value in Wh times 10^multiplier to energy
+func scaleESPIValue(value int64, multiplier int) (zonnegosdk.Energy, error) {
+	if value < 0 {
+		return 0, fmt.Errorf("negative value %d", value)
+	}
+
+	wh := uint64(value)
+	for ; multiplier > 0; multiplier-- {
+		hi, lo := bits.Mul64(wh, 10)
+		if hi != 0 {
+			return 0, fmt.Errorf("value %d overflows", value)
+		}
+		wh = lo
+	}
+	for ; multiplier < 0; multiplier++ {
+		if wh%10 != 0 {
+			return 0, fmt.Errorf("value %d is not a whole number of Wh", value)
+		}
+		wh /= 10
+	}
+	return zonnegosdk.Energy(wh), nil
+}
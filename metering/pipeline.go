@@ -0,0 +1,394 @@
+// Package metering turns interval meter readings into energy token mints.
+//
+// Readings are parsed from CSV (ParseCSV) or Green Button ESPI XML
+// (ParseGreenButton), aggregated per producer and energy type into batches
+// (Aggregate) and minted by a Pipeline. Energy short of a whole token is
+// carried over to the producer's next batch.
+//
+// The pipeline keeps a Checkpoint holding, per meter, the end of the last
+// minted reading. Before a mint is sent, its signature, its mint records and
+// the checkpoint entries it will advance are saved as pending. The entries
+// are applied only once the transaction is finalized, and the pending mint
+// is dropped only once its blockhash has expired without it landing, so a
+// crash at any point never mints a reading twice.
+//
+// The pipeline cannot tell a new meter from one whose checkpoint entry was
+// lost, so a meter without an entry must be given a watermark in the Config:
+// the end of its last minted reading, or the zero time for a meter with
+// nothing minted. Readings of a meter with neither fail with ErrNoWatermark.
+//
+// A ConsumptionBridge does the same for consumers' meters, minting with
+// MintConsumptionTokens the consumption their purchases do not cover.
+//
+//	pipeline, err := metering.NewPipeline(client, metering.Config{
+//		Grid:          grid,
+//		GridAuthority: gridAuthority,
+//		Checkpoint:    metering.NewFileCheckpointStore("meters.checkpoint.json"),
+//	})
+//	readings, err := metering.ParseCSV(file, metering.Source{Wallet: producer, EnergyType: zonnegosdk.EnergyTypeSolar})
+//	report, err := pipeline.Ingest(ctx, readings)
+package metering
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// ErrMintPending is returned while a mint sent earlier has neither landed
+// nor expired. Retry once its blockhash expires, about a minute later.
+var ErrMintPending = errors.New("mint pending: outcome not known yet")
+
+// ErrNoWatermark is returned for readings of a meter that has neither a
+// checkpoint entry nor a watermark
+var ErrNoWatermark = errors.New("meter has no checkpoint entry or watermark")
+
+// Config configures a Pipeline
+type Config struct {
+	Grid solana.PublicKey
+	// GridAuthority signs the mints and pays the transaction fees
+	GridAuthority solana.PrivateKey
+	// ProducerAuthority initializes missing producer accounts and pays their
+	// rent. Defaults to GridAuthority.
+	ProducerAuthority solana.PrivateKey
+	Checkpoint        CheckpointStore
+	// Watermarks maps meters without a checkpoint entry, by Reading.MeterKey,
+	// to the end of their last minted reading. A zero time declares a meter
+	// with nothing minted. Entries of meters in the checkpoint are ignored.
+	Watermarks map[string]time.Time
+}
+
+// Pipeline mints energy tokens for meter readings. It is not safe for
+// concurrent use, and only one pipeline may use a checkpoint at a time.
+type Pipeline struct {
+	client     *zonnegosdk.Client
+	config     Config
+	checkpoint *Checkpoint
+}
+
+// BatchResult is the outcome of one batch
+type BatchResult struct {
+	Batch
+	// Mints are the mint records created, more than one when the amount was
+	// split to avoid a seed collision
+	Mints []zonnegosdk.MintRecordSeeds `json:"mints,omitempty"`
+	// Signature is the mint transaction, nil when the batch was less than
+	// one token and only carried over
+	Signature           *solana.Signature `json:"signature,omitempty"`
+	InitializedProducer bool              `json:"initialized_producer"`
+}
+
+// Report is the outcome of Ingest
+type Report struct {
+	// Recovered is the pending mint of an earlier run found to have landed
+	Recovered *PendingMint `json:"recovered,omitempty"`
+	// Batches are the batches processed, in order. When Ingest fails, the
+	// batches before the failure are reported.
+	Batches []BatchResult `json:"batches"`
+	// Duplicates are the readings skipped because they were already minted
+	Duplicates []Reading `json:"duplicates,omitempty"`
+}
+
+// NewPipeline creates a pipeline and loads its checkpoint
+func NewPipeline(client *zonnegosdk.Client, config Config) (*Pipeline, error) {
+	if config.Grid.IsZero() {
+		return nil, fmt.Errorf("pipeline has no grid")
+	}
+	if err := checkPrivateKey("grid authority", config.GridAuthority); err != nil {
+		return nil, err
+	}
+	if config.ProducerAuthority == nil {
+		config.ProducerAuthority = config.GridAuthority
+	}
+	if err := checkPrivateKey("producer authority", config.ProducerAuthority); err != nil {
+		return nil, err
+	}
+	if config.Checkpoint == nil {
+		return nil, fmt.Errorf("pipeline has no checkpoint store")
+	}
+
+	checkpoint, err := config.Checkpoint.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	for key, end := range config.Watermarks {
+		if _, ok := checkpoint.Meters[key]; !ok {
+			checkpoint.Meters[key] = end
+		}
+	}
+
+	return &Pipeline{client: client, config: config, checkpoint: checkpoint}, nil
+}
+
+// Checkpoint returns the pipeline's current checkpoint
+func (p *Pipeline) Checkpoint() *Checkpoint {
+	return p.checkpoint
+}
+
+// Plan computes the batches Ingest would mint for the readings, without
+// sending them or changing the checkpoint. A pending mint that landed is
+// reported as recovered and taken into account.
+func (p *Pipeline) Plan(ctx context.Context, readings []Reading) (*Report, error) {
+	return p.run(ctx, readings, false)
+}
+
+// Ingest resolves any pending mint, then mints the readings not minted yet,
+// one transaction per producer and energy type, saving the checkpoint after
+// each. Readings may be passed again after a failure; those already minted
+// are skipped.
+func (p *Pipeline) Ingest(ctx context.Context, readings []Reading) (*Report, error) {
+	return p.run(ctx, readings, true)
+}
+
+// run resolves any pending mint and batches the readings, minting the
+// batches if send is set
+func (p *Pipeline) run(ctx context.Context, readings []Reading, send bool) (*Report, error) {
+	report := &Report{}
+
+	checkpoint := p.checkpoint
+	if send {
+		recovered, err := p.Recover(ctx)
+		if err != nil {
+			return report, err
+		}
+		report.Recovered = recovered
+	} else if pending := checkpoint.Pending; pending != nil {
+		landed, err := p.resolve(ctx, pending)
+		if err != nil {
+			return report, err
+		}
+		if landed {
+			checkpoint = checkpoint.clone()
+			checkpoint.apply(pending)
+			report.Recovered = pending
+		}
+	}
+
+	if err := p.checkWatermarks(readings); err != nil {
+		return report, err
+	}
+	batches, duplicates, err := Aggregate(checkpoint, readings)
+	if err != nil {
+		return report, err
+	}
+	report.Duplicates = duplicates
+
+	for _, batch := range batches {
+		var result *BatchResult
+		if send {
+			result, err = p.mint(ctx, batch)
+		} else {
+			result, err = p.plan(ctx, batch)
+		}
+		if err != nil {
+			return report, fmt.Errorf("failed to mint %s for producer %s: %w", batch.Energy, batch.Producer, err)
+		}
+		report.Batches = append(report.Batches, *result)
+	}
+
+	return report, nil
+}
+
+// Recover resolves the pending mint left by an earlier run, if any. It
+// returns the pending mint if it landed, nil if there was none or it expired,
+// and ErrMintPending if its outcome is not known yet.
+func (p *Pipeline) Recover(ctx context.Context) (*PendingMint, error) {
+	pending := p.checkpoint.Pending
+	if pending == nil {
+		return nil, nil
+	}
+
+	landed, err := p.resolve(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+	if landed {
+		return pending, p.commit(pending)
+	}
+
+	// The blockhash expired without the mint landing, so its readings are
+	// still unminted and will be batched again
+	p.checkpoint.Pending = nil
+	return nil, p.config.Checkpoint.Save(p.checkpoint)
+}
+
+// resolve reports whether the pending mint landed, false if its blockhash
+// expired without it, and ErrMintPending if its outcome is not known yet
+func (p *Pipeline) resolve(ctx context.Context, pending *PendingMint) (bool, error) {
+	landed, err := p.landed(ctx, pending)
+	if err != nil || landed {
+		return landed, err
+	}
+
+	height, err := p.client.GetRPCClient().GetBlockHeight(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return false, fmt.Errorf("failed to get block height: %w", err)
+	}
+	if height <= pending.LastValidBlockHeight {
+		return false, fmt.Errorf("transaction %s: %w", pending.Signature, ErrMintPending)
+	}
+	return false, nil
+}
+
+// checkWatermarks fails with ErrNoWatermark if any reading's meter has no
+// checkpoint entry
+func (p *Pipeline) checkWatermarks(readings []Reading) error {
+	var missing []string
+	seen := make(map[string]bool)
+	for _, reading := range readings {
+		key := reading.MeterKey()
+		if _, ok := p.checkpoint.Meters[key]; ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		missing = append(missing, key)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return fmt.Errorf("meters %s: %w", strings.Join(missing, ", "), ErrNoWatermark)
+}
+
+// mint sends the batch's mint and records it in the checkpoint
+func (p *Pipeline) mint(ctx context.Context, batch Batch) (*BatchResult, error) {
+	result := &BatchResult{Batch: batch}
+	pending := &PendingMint{
+		Meters: lastEnds(batch.Readings),
+		Carry:  map[string]zonnegosdk.Energy{streamKey(batch.Producer, batch.EnergyType): batch.Carry},
+	}
+	if batch.Tokens == 0 {
+		return result, p.commit(pending)
+	}
+
+	gridAuthority, producerAuthority := p.config.GridAuthority, p.config.ProducerAuthority
+	plan, err := p.planMint(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, lastValidBlockHeight, err := p.client.BuildUnsignedTransaction(ctx, gridAuthority.PublicKey(), plan.Instructions...)
+	if err != nil {
+		return nil, err
+	}
+	signers := []solana.PrivateKey{gridAuthority}
+	if plan.InitializesProducer && !producerAuthority.PublicKey().Equals(gridAuthority.PublicKey()) {
+		signers = append(signers, producerAuthority)
+	}
+	if err := zonnegosdk.PartialSignTransaction(transaction, signers...); err != nil {
+		return nil, err
+	}
+
+	for _, mint := range plan.Mints {
+		pending.Mints = append(pending.Mints, mint.Seeds)
+	}
+	pending.Signature = transaction.Signatures[0]
+	pending.LastValidBlockHeight = lastValidBlockHeight
+
+	// Record the mint before sending it, so a crash after this point is
+	// resolved by Recover instead of minting the readings again
+	p.checkpoint.Pending = pending
+	if err := p.config.Checkpoint.Save(p.checkpoint); err != nil {
+		p.checkpoint.Pending = nil
+		return nil, err
+	}
+
+	if _, err := p.client.SubmitSignedTransaction(ctx, transaction); err != nil {
+		return nil, err
+	}
+	// A confirmation timeout leaves the outcome to the landed check below
+	if err := p.client.ConfirmTransaction(ctx, pending.Signature); err != nil && !errors.Is(err, zonnegosdk.ErrConfirmationTimeout) {
+		return nil, err
+	}
+
+	landed, err := p.landed(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+	if !landed {
+		return nil, fmt.Errorf("transaction %s: %w", pending.Signature, ErrMintPending)
+	}
+	if err := p.commit(pending); err != nil {
+		return nil, err
+	}
+
+	result.Mints = pending.Mints
+	result.Signature = &pending.Signature
+	result.InitializedProducer = plan.InitializesProducer
+	return result, nil
+}
+
+// plan plans the batch's mint without sending it
+func (p *Pipeline) plan(ctx context.Context, batch Batch) (*BatchResult, error) {
+	result := &BatchResult{Batch: batch}
+	if batch.Tokens == 0 {
+		return result, nil
+	}
+
+	plan, err := p.planMint(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	for _, mint := range plan.Mints {
+		result.Mints = append(result.Mints, mint.Seeds)
+	}
+	result.InitializedProducer = plan.InitializesProducer
+	return result, nil
+}
+
+// planMint builds the instructions minting the batch, splitting the amount
+// on a seed collision
+func (p *Pipeline) planMint(ctx context.Context, batch Batch) (*zonnegosdk.MintPlan, error) {
+	return p.client.PlanMintWithProducer(ctx, zonnegosdk.MintRecordCreationParams{
+		Grid:          p.config.Grid,
+		Producer:      batch.Producer,
+		Amount:        batch.Tokens,
+		EnergyType:    batch.EnergyType,
+		GridAuthority: p.config.GridAuthority.PublicKey(),
+	}, p.config.ProducerAuthority.PublicKey(), zonnegosdk.CollisionSplit)
+}
+
+// commit applies the pending mint to the checkpoint and saves it
+func (p *Pipeline) commit(pending *PendingMint) error {
+	p.checkpoint.apply(pending)
+	return p.config.Checkpoint.Save(p.checkpoint)
+}
+
+// landed reports whether the pending mint's transaction is finalized. Its
+// mint records may also have been created by another grid authority, so
+// their existence alone does not show that this mint landed. It returns
+// ErrMintPending while the transaction is seen but not finalized.
+func (p *Pipeline) landed(ctx context.Context, pending *PendingMint) (bool, error) {
+	statuses, err := p.client.GetRPCClient().GetSignatureStatuses(ctx, true, pending.Signature)
+	if err != nil {
+		return false, fmt.Errorf("failed to get signature status: %w", err)
+	}
+	if len(statuses.Value) == 0 || statuses.Value[0] == nil {
+		return false, nil
+	}
+	status := statuses.Value[0]
+	if status.Err != nil {
+		return false, nil
+	}
+	if status.ConfirmationStatus != rpc.ConfirmationStatusFinalized {
+		return false, fmt.Errorf("transaction %s: %w", pending.Signature, ErrMintPending)
+	}
+	return true, nil
+}
+
+// checkPrivateKey checks that key is a whole ed25519 private key
+func checkPrivateKey(name string, key solana.PrivateKey) error {
+	if len(key) != ed25519.PrivateKeySize {
+		return fmt.Errorf("%s must be a %d-byte private key, got %d bytes", name, ed25519.PrivateKeySize, len(key))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package metering
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/gagliardetto/solana-go"
+)
+
+// Reading is the energy a meter recorded over one interval: produced, for a
+// producer's meter, or consumed, for a consumer's
+type Reading struct {
+	// Wallet is the producer or consumer the meter belongs to
+	Wallet     solana.PublicKey      `json:"wallet"`
+	MeterID    string                `json:"meter_id"`
+	Start      time.Time             `json:"start"`
+	End        time.Time             `json:"end"`
+	Energy     zonnegosdk.Energy     `json:"energy"`
+	EnergyType zonnegosdk.EnergyType `json:"energy_type"`
+}
+
+// Source identifies the wallet, meter and energy type of readings whose data
+// does not carry them
+type Source struct {
+	Wallet     solana.PublicKey
+	MeterID    string
+	EnergyType zonnegosdk.EnergyType
+}
+
+// Validate checks that the reading can be ingested
+func (r Reading) Validate() error {
+	switch {
+	case r.Wallet.IsZero():
+		return fmt.Errorf("reading of meter %q has no wallet", r.MeterID)
+	case r.MeterID == "":
+		return fmt.Errorf("reading of wallet %s has no meter ID", r.Wallet)
+	case !r.End.After(r.Start):
+		return fmt.Errorf("reading of meter %q: end %s is not after start %s", r.MeterID, r.End, r.Start)
+	case !r.EnergyType.IsValid():
+		return fmt.Errorf("reading of meter %q: invalid energy type %d", r.MeterID, uint8(r.EnergyType))
+	}
+	return nil
+}
+
+// MeterKey identifies the reading's meter in Checkpoint.Meters
+func (r Reading) MeterKey() string {
+	return r.Wallet.String() + "/" + r.MeterID
+}
+
+// streamKey identifies the energy of one type produced by a producer, which
+// is minted in the same batches
+func streamKey(producer solana.PublicKey, energyType zonnegosdk.EnergyType) string {
+	return producer.String() + "/" + energyType.String()
+}
+
+// filterReadings sorts the readings by meter and start and splits them into
+// those after their meter's checkpoint and the duplicates: those ending at or
+// before it, and repeats of a reading in the input. A reading overlapping its
+// meter's checkpoint or another reading is an error.
+func filterReadings(meters map[string]time.Time, readings []Reading) ([]Reading, []Reading, error) {
+	sorted := append([]Reading(nil), readings...)
+	for _, reading := range sorted {
+		if err := reading.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if ki, kj := sorted[i].MeterKey(), sorted[j].MeterKey(); ki != kj {
+			return ki < kj
+		}
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	var fresh, duplicates []Reading
+	var previous *Reading
+	for i := range sorted {
+		reading := sorted[i]
+		key := reading.MeterKey()
+		if previous != nil && previous.MeterKey() != key {
+			previous = nil
+		}
+
+		mark := meters[key]
+		if !reading.End.After(mark) {
+			duplicates = append(duplicates, reading)
+			continue
+		}
+		if reading.Start.Before(mark) {
+			return nil, nil, fmt.Errorf("reading of meter %s from %s to %s overlaps its checkpoint at %s", key, reading.Start, reading.End, mark)
+		}
+
+		if previous != nil && reading.Start.Before(previous.End) {
+			if reading.Start.Equal(previous.Start) && reading.End.Equal(previous.End) && reading.Energy == previous.Energy {
+				duplicates = append(duplicates, reading)
+				continue
+			}
+			return nil, nil, fmt.Errorf("readings of meter %s from %s and %s overlap", key, previous.Start, reading.Start)
+		}
+		previous = &sorted[i]
+		fresh = append(fresh, reading)
+	}
+
+	return fresh, duplicates, nil
+}
+
+// lastEnds returns the end of the last reading of each meter
+func lastEnds(readings []Reading) map[string]time.Time {
+	meters := make(map[string]time.Time)
+	for _, reading := range readings {
+		key := reading.MeterKey()
+		if reading.End.After(meters[key]) {
+			meters[key] = reading.End.UTC()
+		}
+	}
+	return meters
+}
@@ -0,0 +1,57 @@
+package oracle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/akbariandev/zonnegosdk/metering"
+	"github.com/gagliardetto/solana-go"
+)
+
+// messagePrefix separates reading attestations from other signed messages
+const messagePrefix = "zonne-reading/v1"
+
+// SignedReading is a meter reading attested by the meter's ed25519 key, as
+// sent by field devices. Times are Unix seconds.
+type SignedReading struct {
+	MeterID   string           `json:"meter_id"`
+	Start     int64            `json:"start"`
+	End       int64            `json:"end"`
+	EnergyWh  uint64           `json:"energy_wh"`
+	Signature solana.Signature `json:"signature"`
+}
+
+// Message returns the bytes the meter signs:
+//
+//	zonne-reading/v1\n<meter_id>\n<start>\n<end>\n<energy_wh>
+func (r SignedReading) Message() []byte {
+	return []byte(fmt.Sprintf("%s\n%s\n%d\n%d\n%d", messagePrefix, r.MeterID, r.Start, r.End, r.EnergyWh))
+}
+
+// Verify reports whether the reading is signed by the meter key
+func (r SignedReading) Verify(meterKey solana.PublicKey) bool {
+	return r.Signature.Verify(meterKey, r.Message())
+}
+
+// SignReading signs a reading with the meter key
+func SignReading(key solana.PrivateKey, reading SignedReading) (SignedReading, error) {
+	signature, err := key.Sign(reading.Message())
+	if err != nil {
+		return SignedReading{}, fmt.Errorf("failed to sign reading: %w", err)
+	}
+	reading.Signature = signature
+	return reading, nil
+}
+
+// reading converts the attestation to a reading of the registered meter
+func (r SignedReading) reading(meter Meter) metering.Reading {
+	return metering.Reading{
+		Wallet:     meter.Producer,
+		MeterID:    r.MeterID,
+		Start:      time.Unix(r.Start, 0).UTC(),
+		End:        time.Unix(r.End, 0).UTC(),
+		Energy:     zonnegosdk.Energy(r.EnergyWh),
+		EnergyType: meter.EnergyType,
+	}
+}
@@ -0,0 +1,214 @@
+package oracle
+
+import (
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/gagliardetto/solana-go"
+	"gopkg.in/yaml.v3"
+)
+
+// Config defaults
+const (
+	DefaultCheckpointPath = "oracle.checkpoint.json"
+	DefaultQueuePath      = "oracle.queue.jsonl"
+	DefaultFlushInterval  = 15 * time.Minute
+	DefaultFlushSize      = 500
+	DefaultMaxClockSkew   = 5 * time.Minute
+	DefaultMaxReadingAge  = 7 * 24 * time.Hour
+)
+
+// Config configures an Oracle
+type Config struct {
+	// Grid defaults to the grid authority's public key
+	Grid solana.PublicKey `yaml:"grid" toml:"grid"`
+	// CheckpointPath is the metering checkpoint file
+	CheckpointPath string `yaml:"checkpoint" toml:"checkpoint"`
+	// QueuePath is the file holding accepted readings not minted yet
+	QueuePath string `yaml:"queue" toml:"queue"`
+	// FlushInterval is how often queued readings are minted
+	FlushInterval time.Duration `yaml:"flush_interval" toml:"flush_interval"`
+	// FlushSize is the queue length that triggers a mint before the interval
+	FlushSize int `yaml:"flush_size" toml:"flush_size"`
+	// MaxClockSkew is how far past the oracle's clock a reading may end
+	MaxClockSkew time.Duration `yaml:"max_clock_skew" toml:"max_clock_skew"`
+	// MaxReadingAge is how far before the oracle's clock a reading may start
+	MaxReadingAge time.Duration `yaml:"max_reading_age" toml:"max_reading_age"`
+	Meters        []Meter       `yaml:"meters" toml:"meters"`
+	// DefaultLimit applies to producers without an entry in Limits
+	DefaultLimit *Limit          `yaml:"default_limit" toml:"default_limit"`
+	Limits       []ProducerLimit `yaml:"limits" toml:"limits"`
+}
+
+// Meter is a field device registered with the oracle
+type Meter struct {
+	ID string `yaml:"id" toml:"id"`
+	// PublicKey verifies the meter's reading signatures
+	PublicKey  solana.PublicKey      `yaml:"public_key" toml:"public_key"`
+	Producer   solana.PublicKey      `yaml:"producer" toml:"producer"`
+	EnergyType zonnegosdk.EnergyType `yaml:"energy_type" toml:"energy_type"`
+	// Commissioned, when set, is the earliest time the meter's readings may
+	// start. It is also the meter's watermark while the checkpoint has no
+	// entry for it: after restoring a lost checkpoint, set it to the end of
+	// the meter's last minted reading.
+	Commissioned time.Time `yaml:"commissioned" toml:"commissioned"`
+}
+
+// Limit caps the energy a producer's meter may report: at most MaxEnergy
+// per Interval, prorated to the reading's duration
+type Limit struct {
+	MaxEnergy zonnegosdk.Energy `yaml:"max_energy" toml:"max_energy"`
+	Interval  time.Duration     `yaml:"interval" toml:"interval"`
+}
+
+// ProducerLimit is the limit of one producer
+type ProducerLimit struct {
+	Producer solana.PublicKey `yaml:"producer" toml:"producer"`
+	Limit    `yaml:",inline"`
+}
+
+// Allows reports whether a reading of the energy over the duration is within
+// the limit. A reading longer than the interval is never allowed, so a meter
+// cannot spread a burst over a long reading.
+func (l Limit) Allows(energy zonnegosdk.Energy, duration time.Duration) bool {
+	if duration <= 0 || duration > l.Interval {
+		return false
+	}
+	// energy / duration <= MaxEnergy / Interval, without overflow
+	hi1, lo1 := bits.Mul64(uint64(energy), uint64(l.Interval))
+	hi2, lo2 := bits.Mul64(uint64(l.MaxEnergy), uint64(duration))
+	return hi1 < hi2 || hi1 == hi2 && lo1 <= lo2
+}
+
+func (l Limit) validate() error {
+	if l.MaxEnergy == 0 {
+		return fmt.Errorf("max energy must be positive")
+	}
+	if l.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	return nil
+}
+
+// String returns the limit as, for example, "5kWh per 15m0s"
+func (l Limit) String() string {
+	return fmt.Sprintf("%s per %s", l.MaxEnergy, l.Interval)
+}
+
+// ParseConfig parses an oracle config in the given format: "yaml" or "toml"
+func ParseConfig(data []byte, format string) (*Config, error) {
+	var config Config
+	var err error
+	switch format {
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &config)
+	case "toml":
+		err = toml.Unmarshal(data, &config)
+	default:
+		return nil, fmt.Errorf("unsupported oracle config format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s oracle config: %w", format, err)
+	}
+	return &config, nil
+}
+
+// LoadConfig reads an oracle config file, choosing the format from its
+// extension (.yaml, .yml or .toml)
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read oracle config: %w", err)
+	}
+	return ParseConfig(data, strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// withDefaults returns the config with unset fields defaulted
+func (c Config) withDefaults() Config {
+	if c.CheckpointPath == "" {
+		c.CheckpointPath = DefaultCheckpointPath
+	}
+	if c.QueuePath == "" {
+		c.QueuePath = DefaultQueuePath
+	}
+	if c.FlushInterval == 0 {
+		c.FlushInterval = DefaultFlushInterval
+	}
+	if c.FlushSize == 0 {
+		c.FlushSize = DefaultFlushSize
+	}
+	if c.MaxClockSkew == 0 {
+		c.MaxClockSkew = DefaultMaxClockSkew
+	}
+	if c.MaxReadingAge == 0 {
+		c.MaxReadingAge = DefaultMaxReadingAge
+	}
+	return c
+}
+
+// Validate checks that every meter is complete and unique and that every
+// producer has a limit
+func (c Config) Validate() error {
+	if c.FlushInterval < 0 || c.FlushSize < 0 || c.MaxClockSkew < 0 || c.MaxReadingAge < 0 {
+		return fmt.Errorf("flush interval, flush size, max clock skew and max reading age must not be negative")
+	}
+	if len(c.Meters) == 0 {
+		return fmt.Errorf("no meters configured")
+	}
+
+	limits := make(map[solana.PublicKey]bool)
+	for _, limit := range c.Limits {
+		if limit.Producer.IsZero() {
+			return fmt.Errorf("limit has no producer")
+		}
+		if limits[limit.Producer] {
+			return fmt.Errorf("producer %s has more than one limit", limit.Producer)
+		}
+		if err := limit.validate(); err != nil {
+			return fmt.Errorf("limit of producer %s: %w", limit.Producer, err)
+		}
+		limits[limit.Producer] = true
+	}
+	if c.DefaultLimit != nil {
+		if err := c.DefaultLimit.validate(); err != nil {
+			return fmt.Errorf("default limit: %w", err)
+		}
+	}
+
+	ids := make(map[string]bool)
+	for _, meter := range c.Meters {
+		switch {
+		case meter.ID == "":
+			return fmt.Errorf("meter has no ID")
+		case ids[meter.ID]:
+			return fmt.Errorf("meter %q is registered more than once", meter.ID)
+		case meter.PublicKey.IsZero():
+			return fmt.Errorf("meter %q has no public key", meter.ID)
+		case meter.Producer.IsZero():
+			return fmt.Errorf("meter %q has no producer", meter.ID)
+		case !meter.EnergyType.IsValid():
+			return fmt.Errorf("meter %q: invalid energy type %d", meter.ID, uint8(meter.EnergyType))
+		case !limits[meter.Producer] && c.DefaultLimit == nil:
+			return fmt.Errorf("meter %q: producer %s has no limit and no default limit is set", meter.ID, meter.Producer)
+		}
+		ids[meter.ID] = true
+	}
+
+	return nil
+}
+
+// limit returns the producer's limit
+func (c Config) limit(producer solana.PublicKey) Limit {
+	for _, limit := range c.Limits {
+		if limit.Producer.Equals(producer) {
+			return limit.Limit
+		}
+	}
+	return *c.DefaultLimit
+}
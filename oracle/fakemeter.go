@@ -0,0 +1,125 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/gagliardetto/solana-go"
+)
+
+// maxReadingsPerRequest limits the readings a FakeMeter sends at once
+const maxReadingsPerRequest = 500
+
+// FakeMeter simulates a field meter producing constant power, for testing an
+// oracle locally. Register its ID and public key in the oracle's config.
+type FakeMeter struct {
+	ID  string
+	Key solana.PrivateKey
+	// Power is the energy produced per hour
+	Power    zonnegosdk.Energy
+	Interval time.Duration
+	// HTTPClient sends the readings. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// NewFakeMeter creates a fake meter
+func NewFakeMeter(id string, key solana.PrivateKey, power zonnegosdk.Energy, interval time.Duration) *FakeMeter {
+	return &FakeMeter{ID: id, Key: key, Power: power, Interval: interval}
+}
+
+// Reading returns the signed reading of the interval starting at start
+func (m *FakeMeter) Reading(start time.Time) (SignedReading, error) {
+	if m.Interval < time.Second {
+		return SignedReading{}, fmt.Errorf("fake meter interval %s is shorter than a second", m.Interval)
+	}
+	hi, lo := bits.Mul64(uint64(m.Power), uint64(m.Interval))
+	if hi >= uint64(time.Hour) {
+		return SignedReading{}, fmt.Errorf("fake meter energy overflows")
+	}
+	energy, _ := bits.Div64(hi, lo, uint64(time.Hour))
+
+	return SignReading(m.Key, SignedReading{
+		MeterID:  m.ID,
+		Start:    start.Unix(),
+		End:      start.Add(m.Interval).Unix(),
+		EnergyWh: energy,
+	})
+}
+
+// Submit sends the readings to the oracle at baseURL
+func (m *FakeMeter) Submit(ctx context.Context, baseURL string, readings ...SignedReading) (*SubmitResult, error) {
+	return SubmitReadings(ctx, m.HTTPClient, baseURL, readings)
+}
+
+// Run submits a reading for every interval from start, catching up on the
+// intervals already past, then one per interval as each ends, until the
+// context is canceled
+func (m *FakeMeter) Run(ctx context.Context, baseURL string, start time.Time) error {
+	next := start.Truncate(m.Interval)
+	for {
+		var readings []SignedReading
+		for !next.Add(m.Interval).After(time.Now()) && len(readings) < maxReadingsPerRequest {
+			reading, err := m.Reading(next)
+			if err != nil {
+				return err
+			}
+			readings = append(readings, reading)
+			next = next.Add(m.Interval)
+		}
+		if len(readings) > 0 {
+			if _, err := m.Submit(ctx, baseURL, readings...); err != nil {
+				return err
+			}
+			continue
+		}
+
+		timer := time.NewTimer(time.Until(next.Add(m.Interval)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// SubmitReadings posts signed readings to the oracle at baseURL, as a field
+// device does. A nil client uses http.DefaultClient.
+func SubmitReadings(ctx context.Context, client *http.Client, baseURL string, readings []SignedReading) (*SubmitResult, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	body, err := json.Marshal(SubmitRequest{Readings: readings})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode readings: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(baseURL, "/")+"/v1/readings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit readings: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusAccepted {
+		var errResponse ErrorResponse
+		json.NewDecoder(response.Body).Decode(&errResponse)
+		return nil, fmt.Errorf("oracle rejected readings: %s: %s", response.Status, errResponse.Error)
+	}
+	var result SubmitResult
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode submit result: %w", err)
+	}
+	return &result, nil
+}
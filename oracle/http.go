@@ -0,0 +1,93 @@
+package oracle
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// maxBodyBytes limits the size of request bodies
+const maxBodyBytes = 1 << 20
+
+// SubmitRequest is the body of POST /v1/readings
+type SubmitRequest struct {
+	Readings []SignedReading `json:"readings"`
+}
+
+// ErrorResponse is the body of an error response
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler returns the oracle's HTTP API:
+//
+//	POST /v1/readings  submit signed readings, answered with a SubmitResult
+//	GET  /v1/status    the oracle's Status
+//
+// A rejected submission is answered with 401 for an unknown meter or a bad
+// signature, 400 for a malformed reading, 409 for a conflicting reading and
+// 422 for a reading above its limit, in the future or too old.
+func (o *Oracle) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/readings", o.handleReadings)
+	mux.HandleFunc("/v1/status", o.handleStatus)
+	return mux
+}
+
+func (o *Oracle) handleReadings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		o.writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "method not allowed"})
+		return
+	}
+
+	var request SubmitRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		o.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body: " + err.Error()})
+		return
+	}
+
+	result, err := o.Submit(request.Readings)
+	if err != nil {
+		o.writeError(w, err)
+		return
+	}
+	o.writeJSON(w, http.StatusAccepted, result)
+}
+
+func (o *Oracle) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		o.writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "method not allowed"})
+		return
+	}
+	o.writeJSON(w, http.StatusOK, o.Status())
+}
+
+// writeError maps a Submit error to a status code and writes it. Errors
+// other than rejected readings, such as a failed queue write, are 500.
+func (o *Oracle) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrUnknownMeter), errors.Is(err, ErrBadSignature):
+		status = http.StatusUnauthorized
+	case errors.Is(err, ErrInvalidReading):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, ErrLimitExceeded), errors.Is(err, ErrFutureReading), errors.Is(err, ErrStaleReading):
+		status = http.StatusUnprocessableEntity
+	}
+	o.writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
+
+// writeJSON writes v as a JSON response, logging a failure to encode or send it
+func (o *Oracle) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		o.logger.Printf("failed to write %d response: %v", status, err)
+	}
+}
@@ -0,0 +1,337 @@
+// Package oracle is a grid authority service that mints energy tokens for
+// readings reported by field meters.
+//
+// Each meter is registered with an ed25519 public key and signs its readings
+// (SignReading). The oracle accepts them over HTTP (Handler), verifies the
+// signature, checks the reading against its producer's limit, appends it to
+// a durable queue and mints the queue in batches with a metering.Pipeline,
+// every FlushInterval or once FlushSize readings are queued. The pipeline's
+// checkpoint guarantees a reading is minted at most once, even across
+// crashes.
+//
+// Readings of a meter must arrive in time order. A reading ending before one
+// already minted is ignored as a duplicate. A reading may not be longer than
+// its producer's limit interval, nor start before its meter was commissioned
+// or more than MaxReadingAge ago.
+//
+//	config, err := oracle.LoadConfig("oracle.yaml")
+//	o, err := oracle.New(client, gridAuthority, *config)
+//	go o.Run(ctx)
+//	log.Fatal(http.ListenAndServe(":8081", o.Handler()))
+//
+// FakeMeter simulates a field device for local testing.
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/akbariandev/zonnegosdk/metering"
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	// ErrUnknownMeter is returned for a reading from an unregistered meter
+	ErrUnknownMeter = errors.New("unknown meter")
+	// ErrBadSignature is returned for a reading not signed by its meter's key
+	ErrBadSignature = errors.New("invalid reading signature")
+	// ErrInvalidReading is returned for a malformed reading
+	ErrInvalidReading = errors.New("invalid reading")
+	// ErrFutureReading is returned for a reading ending after the oracle's
+	// clock, beyond the allowed skew
+	ErrFutureReading = errors.New("reading ends in the future")
+	// ErrStaleReading is returned for a reading starting before its meter was
+	// commissioned or more than MaxReadingAge before the oracle's clock
+	ErrStaleReading = errors.New("reading starts too early")
+	// ErrLimitExceeded is returned for a reading above its producer's limit
+	// or longer than the limit's interval
+	ErrLimitExceeded = errors.New("reading exceeds producer limit")
+	// ErrConflict is returned for a reading overlapping another reading of
+	// its meter
+	ErrConflict = errors.New("reading conflicts with an earlier reading")
+)
+
+// Oracle verifies, queues and mints meter readings. Its methods are safe for
+// concurrent use.
+type Oracle struct {
+	config Config
+	meters map[string]Meter
+	logger *log.Logger
+	now    func() time.Time
+
+	// flushMu serializes flushes, which use the pipeline
+	flushMu  sync.Mutex
+	pipeline *metering.Pipeline
+
+	// mu guards the queue, the minted watermarks and the status
+	mu     sync.Mutex
+	queue  *queue
+	minted map[string]time.Time
+	status Status
+
+	flushNow chan struct{}
+}
+
+// Option configures an Oracle
+type Option func(*Oracle)
+
+// WithLogger sets the logger Run reports flushes to and the HTTP API reports
+// responses that fail to be written to. Defaults to the standard logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(o *Oracle) {
+		o.logger = logger
+	}
+}
+
+// SubmitResult is the outcome of Submit
+type SubmitResult struct {
+	// Accepted is the number of readings queued
+	Accepted int `json:"accepted"`
+	// Duplicates is the number of readings skipped because they were already
+	// queued or minted
+	Duplicates int `json:"duplicates"`
+	// Queued is the queue length after the submission
+	Queued int `json:"queued"`
+}
+
+// Status is the state of the oracle
+type Status struct {
+	Queued    int        `json:"queued"`
+	LastFlush *time.Time `json:"last_flush,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	// Minted is, per meter ID, the end of the last minted reading
+	Minted map[string]time.Time `json:"minted"`
+	// Tokens is the number of tokens minted since the oracle started
+	Tokens uint64 `json:"tokens"`
+}
+
+// New creates an oracle minting as the grid authority. It loads the
+// checkpoint and queue named by the config, creating them if needed.
+func New(client *zonnegosdk.Client, gridAuthority solana.PrivateKey, config Config, opts ...Option) (*Oracle, error) {
+	config = config.withDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid oracle config: %w", err)
+	}
+	if config.Grid.IsZero() && gridAuthority != nil {
+		config.Grid = gridAuthority.PublicKey()
+	}
+
+	o := &Oracle{
+		config:   config,
+		meters:   make(map[string]Meter, len(config.Meters)),
+		logger:   log.Default(),
+		now:      time.Now,
+		flushNow: make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	for _, meter := range config.Meters {
+		o.meters[meter.ID] = meter
+	}
+
+	// Readings start no earlier than their meter's commissioning, so nothing
+	// before it can have been minted by this oracle
+	watermarks := make(map[string]time.Time, len(config.Meters))
+	for _, meter := range config.Meters {
+		watermarks[metering.Reading{Wallet: meter.Producer, MeterID: meter.ID}.MeterKey()] = meter.Commissioned
+	}
+	pipeline, err := metering.NewPipeline(client, metering.Config{
+		Grid:          config.Grid,
+		GridAuthority: gridAuthority,
+		Checkpoint:    metering.NewFileCheckpointStore(config.CheckpointPath),
+		Watermarks:    watermarks,
+	})
+	if err != nil {
+		return nil, err
+	}
+	o.pipeline = pipeline
+
+	if o.queue, err = openQueue(config.QueuePath); err != nil {
+		return nil, err
+	}
+	// Drop readings minted by a flush that stopped before pruning the queue
+	if err := o.sync(); err != nil {
+		o.queue.close()
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// Submit verifies the readings and queues those not queued or minted yet.
+// The readings are accepted or rejected together: if any fails
+// verification, none is queued and the error names the first that failed.
+func (o *Oracle) Submit(signed []SignedReading) (*SubmitResult, error) {
+	readings := make([]metering.Reading, len(signed))
+	now := o.now()
+	skewed := now.Add(o.config.MaxClockSkew)
+	oldest := now.Add(-o.config.MaxReadingAge)
+	for i, s := range signed {
+		meter, ok := o.meters[s.MeterID]
+		if !ok {
+			return nil, fmt.Errorf("reading %d: meter %q: %w", i, s.MeterID, ErrUnknownMeter)
+		}
+		if !s.Verify(meter.PublicKey) {
+			return nil, fmt.Errorf("reading %d of meter %q: %w", i, s.MeterID, ErrBadSignature)
+		}
+		reading := s.reading(meter)
+		if err := reading.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidReading, err)
+		}
+		if reading.End.After(skewed) {
+			return nil, fmt.Errorf("reading %d of meter %q ends at %s: %w", i, s.MeterID, reading.End, ErrFutureReading)
+		}
+		if reading.Start.Before(oldest) || reading.Start.Before(meter.Commissioned) {
+			return nil, fmt.Errorf("reading %d of meter %q starts at %s: %w", i, s.MeterID, reading.Start, ErrStaleReading)
+		}
+		if limit := o.config.limit(meter.Producer); !limit.Allows(reading.Energy, reading.End.Sub(reading.Start)) {
+			return nil, fmt.Errorf("reading %d of meter %q: %s over %s is above %s: %w", i, s.MeterID, reading.Energy, reading.End.Sub(reading.Start), limit, ErrLimitExceeded)
+		}
+		readings[i] = reading
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	queued := o.queue.snapshot()
+	if _, _, err := metering.Aggregate(&metering.Checkpoint{Meters: o.minted}, append(queued, readings...)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+	}
+
+	seen := make(map[string]bool, len(queued)+len(readings))
+	for _, reading := range queued {
+		seen[readingKey(reading)] = true
+	}
+	result := &SubmitResult{}
+	var accepted []metering.Reading
+	for _, reading := range readings {
+		key := readingKey(reading)
+		if seen[key] || !reading.End.After(o.minted[reading.MeterKey()]) {
+			result.Duplicates++
+			continue
+		}
+		seen[key] = true
+		accepted = append(accepted, reading)
+	}
+	if err := o.queue.append(accepted); err != nil {
+		return nil, err
+	}
+
+	result.Accepted = len(accepted)
+	result.Queued = o.queue.len()
+	if result.Queued >= o.config.FlushSize {
+		select {
+		case o.flushNow <- struct{}{}:
+		default:
+		}
+	}
+	return result, nil
+}
+
+// readingKey identifies a reading and its energy
+func readingKey(reading metering.Reading) string {
+	return fmt.Sprintf("%s/%d/%d/%d", reading.MeterKey(), reading.Start.Unix(), reading.End.Unix(), reading.Energy)
+}
+
+// Flush mints the queued readings and removes those minted from the queue.
+// Readings submitted during a flush are minted by the next one.
+func (o *Oracle) Flush(ctx context.Context) (*metering.Report, error) {
+	o.flushMu.Lock()
+	defer o.flushMu.Unlock()
+
+	o.mu.Lock()
+	readings := o.queue.snapshot()
+	o.mu.Unlock()
+
+	report, err := o.pipeline.Ingest(ctx, readings)
+
+	o.mu.Lock()
+	flushed := o.now()
+	o.status.LastFlush = &flushed
+	o.status.LastError = ""
+	if err != nil {
+		o.status.LastError = err.Error()
+	}
+	for _, batch := range report.Batches {
+		o.status.Tokens += batch.Tokens
+	}
+	o.mu.Unlock()
+
+	// Batches minted before a failure are removed from the queue too
+	if syncErr := o.sync(); syncErr != nil && err == nil {
+		err = syncErr
+	}
+	return report, err
+}
+
+// sync copies the pipeline's watermarks and drops the readings they cover
+// from the queue. The caller must hold flushMu or own the oracle exclusively.
+func (o *Oracle) sync() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.minted = make(map[string]time.Time, len(o.pipeline.Checkpoint().Meters))
+	for key, end := range o.pipeline.Checkpoint().Meters {
+		o.minted[key] = end
+	}
+	return o.queue.retain(func(reading metering.Reading) bool {
+		return reading.End.After(o.minted[reading.MeterKey()])
+	})
+}
+
+// Run flushes the queue every FlushInterval, and as soon as FlushSize
+// readings are queued, until the context is canceled. Flush errors are
+// logged and retried on the next flush.
+func (o *Oracle) Run(ctx context.Context) error {
+	ticker := time.NewTicker(o.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-o.flushNow:
+		}
+
+		report, err := o.Flush(ctx)
+		if report != nil {
+			for _, batch := range report.Batches {
+				o.logger.Printf("minted %d tokens to %s for %d readings (%s carried)", batch.Tokens, batch.Producer, len(batch.Readings), batch.Carry)
+			}
+		}
+		if err != nil && ctx.Err() == nil {
+			o.logger.Printf("flush failed: %v", err)
+		}
+	}
+}
+
+// Status returns the state of the oracle
+func (o *Oracle) Status() Status {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	status := o.status
+	status.Queued = o.queue.len()
+	status.Minted = make(map[string]time.Time)
+	for id, meter := range o.meters {
+		key := metering.Reading{Wallet: meter.Producer, MeterID: id}.MeterKey()
+		if end := o.minted[key]; !end.IsZero() {
+			status.Minted[id] = end
+		}
+	}
+	return status
+}
+
+// Close closes the queue. Readings still queued are minted after a restart.
+func (o *Oracle) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.queue.close()
+}
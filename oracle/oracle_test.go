@@ -0,0 +1,416 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/akbariandev/zonnegosdk/metering"
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+const testInterval = 15 * time.Minute
+
+// unreachableRPC is the RPC endpoint of oracles whose tests never flush
+const unreachableRPC = "http://127.0.0.1:0"
+
+// rpcStub is a JSON-RPC endpoint that lands every transaction sent to it:
+// the accounts the transaction references exist from then on and its
+// signature is finalized
+type rpcStub struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	accounts map[solana.PublicKey]bool
+	landed   map[solana.Signature]bool
+	height   uint64
+	failSend bool
+}
+
+func newRPCStub(t *testing.T) *rpcStub {
+	t.Helper()
+	stub := &rpcStub{accounts: make(map[solana.PublicKey]bool), landed: make(map[solana.Signature]bool), height: 100}
+	stub.Server = httptest.NewServer(http.HandlerFunc(stub.serve))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func (s *rpcStub) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var request struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	context := map[string]interface{}{"slot": 1}
+	account := func(param json.RawMessage) interface{} {
+		var address solana.PublicKey
+		if err := json.Unmarshal(param, &address); err != nil || !s.accounts[address] {
+			return nil
+		}
+		return map[string]interface{}{"lamports": 1, "owner": solana.SystemProgramID, "data": []string{"", "base64"}, "executable": false, "rentEpoch": 0}
+	}
+
+	var result interface{}
+	switch request.Method {
+	case "getAccountInfo":
+		result = map[string]interface{}{"context": context, "value": account(request.Params[0])}
+	case "getMultipleAccounts":
+		var addresses []json.RawMessage
+		json.Unmarshal(request.Params[0], &addresses)
+		values := make([]interface{}, len(addresses))
+		for i, address := range addresses {
+			values[i] = account(address)
+		}
+		result = map[string]interface{}{"context": context, "value": values}
+	case "getLatestBlockhash":
+		result = map[string]interface{}{"context": context, "value": map[string]interface{}{"blockhash": solana.Hash{1}.String(), "lastValidBlockHeight": s.height + 150}}
+	case "getBlockHeight":
+		result = s.height
+	case "sendTransaction":
+		if s.failSend {
+			json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "error": map[string]interface{}{"code": -32002, "message": "send failed"}})
+			return
+		}
+		var encoded string
+		json.Unmarshal(request.Params[0], &encoded)
+		data, _ := base64.StdEncoding.DecodeString(encoded)
+		transaction, err := solana.TransactionFromDecoder(bin.NewBinDecoder(data))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, instruction := range transaction.Message.Instructions {
+			for _, index := range instruction.Accounts {
+				s.accounts[transaction.Message.AccountKeys[index]] = true
+			}
+		}
+		s.landed[transaction.Signatures[0]] = true
+		result = transaction.Signatures[0].String()
+	case "getSignatureStatuses":
+		var signatures []solana.Signature
+		json.Unmarshal(request.Params[0], &signatures)
+		statuses := make([]interface{}, len(signatures))
+		for i, signature := range signatures {
+			if s.landed[signature] {
+				statuses[i] = map[string]interface{}{"slot": 1, "confirmations": nil, "err": nil, "confirmationStatus": "finalized"}
+			}
+		}
+		result = map[string]interface{}{"context": context, "value": statuses}
+	default:
+		http.Error(w, "unexpected method "+request.Method, http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+}
+
+// newTestOracle starts an oracle using the RPC endpoint with one solar meter
+// limited to 1kWh per 15 minutes and serves its API
+func newTestOracle(t *testing.T, endpoint string) (*Oracle, *httptest.Server, *FakeMeter, Config) {
+	t.Helper()
+
+	meterKey := solana.NewWallet().PrivateKey
+	dir := t.TempDir()
+	config := Config{
+		CheckpointPath: filepath.Join(dir, "checkpoint.json"),
+		QueuePath:      filepath.Join(dir, "queue.jsonl"),
+		Meters: []Meter{{
+			ID:         "roof-1",
+			PublicKey:  meterKey.PublicKey(),
+			Producer:   solana.NewWallet().PublicKey(),
+			EnergyType: zonnegosdk.EnergyTypeSolar,
+		}},
+		DefaultLimit: &Limit{MaxEnergy: zonnegosdk.KilowattHour, Interval: testInterval},
+	}
+
+	client := zonnegosdk.NewClientWithCustomProgram(endpoint, solana.SystemProgramID)
+	o, err := New(client, solana.NewWallet().PrivateKey, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(o.Handler())
+	t.Cleanup(func() {
+		server.Close()
+		o.Close()
+	})
+
+	// 2kWh per hour is 500Wh per 15 minute reading
+	return o, server, NewFakeMeter("roof-1", meterKey, 2*zonnegosdk.KilowattHour, testInterval), config
+}
+
+// readings returns the meter's signed readings of n intervals from start
+func readings(t *testing.T, meter *FakeMeter, start time.Time, n int) []SignedReading {
+	t.Helper()
+	var signed []SignedReading
+	for i := 0; i < n; i++ {
+		reading, err := meter.Reading(start.Add(time.Duration(i) * meter.Interval))
+		if err != nil {
+			t.Fatal(err)
+		}
+		signed = append(signed, reading)
+	}
+	return signed
+}
+
+// post submits the readings and returns the response status
+func post(t *testing.T, server *httptest.Server, readings ...SignedReading) int {
+	t.Helper()
+	body, err := json.Marshal(SubmitRequest{Readings: readings})
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := http.Post(server.URL+"/v1/readings", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	return response.StatusCode
+}
+
+func TestSubmit(t *testing.T) {
+	_, server, meter, _ := newTestOracle(t, unreachableRPC)
+	start := time.Now().Add(-2 * time.Hour).Truncate(testInterval)
+	ctx := context.Background()
+
+	valid := readings(t, meter, start, 4)
+	if status := post(t, server, valid[0]); status != http.StatusAccepted {
+		t.Fatalf("valid reading: status %d, want %d", status, http.StatusAccepted)
+	}
+	result, err := meter.Submit(ctx, server.URL, valid[1:]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Accepted != 3 || result.Duplicates != 0 || result.Queued != 4 {
+		t.Errorf("submit result is %+v, want 3 accepted and 4 queued", *result)
+	}
+
+	result, err = meter.Submit(ctx, server.URL, valid...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Accepted != 0 || result.Duplicates != 4 || result.Queued != 4 {
+		t.Errorf("resubmit result is %+v, want 4 duplicates and 4 queued", *result)
+	}
+
+	next := start.Add(4 * testInterval)
+	tampered := readings(t, meter, next, 1)[0]
+	tampered.EnergyWh++
+
+	unknown := *meter
+	unknown.ID = "unknown"
+
+	strong := *meter
+	strong.Power = 8 * zonnegosdk.KilowattHour
+
+	long := *meter
+	long.Interval = 2 * testInterval
+
+	// Overlaps the last valid reading without repeating it
+	overlapping := readings(t, meter, start.Add(3*testInterval+5*time.Minute), 1)[0]
+
+	tests := []struct {
+		name    string
+		reading SignedReading
+		status  int
+	}{
+		{"bad signature", tampered, http.StatusUnauthorized},
+		{"unknown meter", readings(t, &unknown, next, 1)[0], http.StatusUnauthorized},
+		{"over limit", readings(t, &strong, next, 1)[0], http.StatusUnprocessableEntity},
+		{"longer than limit interval", readings(t, &long, next, 1)[0], http.StatusUnprocessableEntity},
+		{"future", readings(t, meter, time.Now().Add(time.Hour).Truncate(testInterval), 1)[0], http.StatusUnprocessableEntity},
+		{"too old", readings(t, meter, time.Now().Add(-DefaultMaxReadingAge-time.Hour), 1)[0], http.StatusUnprocessableEntity},
+		{"overlap", overlapping, http.StatusConflict},
+	}
+	for _, test := range tests {
+		if status := post(t, server, test.reading); status != test.status {
+			t.Errorf("%s: status %d, want %d", test.name, status, test.status)
+		}
+	}
+
+	// A rejected reading rejects the whole submission
+	if status := post(t, server, readings(t, meter, next, 1)[0], tampered); status != http.StatusUnauthorized {
+		t.Errorf("mixed submission: status %d, want %d", status, http.StatusUnauthorized)
+	}
+	response, err := http.Get(server.URL + "/v1/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	var status Status
+	if err := json.NewDecoder(response.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Queued != 4 {
+		t.Errorf("%d readings queued, want 4", status.Queued)
+	}
+}
+
+func TestQueuePersistsAcrossRestarts(t *testing.T) {
+	o, server, meter, config := newTestOracle(t, unreachableRPC)
+	start := time.Now().Add(-2 * time.Hour).Truncate(testInterval)
+
+	if _, err := meter.Submit(context.Background(), server.URL, readings(t, meter, start, 3)...); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := openQueue(config.QueuePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.len() != 3 {
+		t.Fatalf("reopened queue holds %d readings, want 3", q.len())
+	}
+
+	// Drop the first reading, then tear the last line as a crash during
+	// append would
+	first := q.snapshot()[0]
+	if err := q.retain(func(reading metering.Reading) bool { return !reading.Start.Equal(first.Start) }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.file.WriteString(`{"wallet":`); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err = openQueue(config.QueuePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.len() != 2 {
+		t.Fatalf("queue holds %d readings after retain and a torn append, want 2", q.len())
+	}
+	for _, reading := range q.snapshot() {
+		if reading.Start.Equal(first.Start) {
+			t.Errorf("dropped reading from %s is still queued", first.Start)
+		}
+	}
+	data, err := os.ReadFile(config.QueuePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		t.Error("torn line was not truncated")
+	}
+
+	if err := q.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restarted oracle counts the persisted readings as duplicates
+	client := zonnegosdk.NewClientWithCustomProgram(unreachableRPC, solana.SystemProgramID)
+	restarted, err := New(client, solana.NewWallet().PrivateKey, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restarted.Close()
+	result, err := restarted.Submit(readings(t, meter, start, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Accepted != 1 || result.Duplicates != 2 || result.Queued != 3 {
+		t.Errorf("submit after restart is %+v, want 1 accepted, 2 duplicates and 3 queued", *result)
+	}
+}
+
+// set changes the stub's state under its lock
+func (s *rpcStub) set(change func(s *rpcStub)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	change(s)
+}
+
+// sent returns the number of transactions that landed
+func (s *rpcStub) sent() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.landed)
+}
+
+func TestFlush(t *testing.T) {
+	stub := newRPCStub(t)
+	o, server, meter, _ := newTestOracle(t, stub.URL)
+	start := time.Now().Add(-2 * time.Hour).Truncate(testInterval)
+	ctx := context.Background()
+
+	if _, err := meter.Submit(ctx, server.URL, readings(t, meter, start, 4)...); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed mint leaves the readings queued, and they are minted again
+	// only once its blockhash has expired
+	stub.set(func(s *rpcStub) { s.failSend = true })
+	if _, err := o.Flush(ctx); err == nil {
+		t.Fatal("flush succeeded although the mint could not be sent")
+	}
+	if status := o.Status(); status.Queued != 4 || status.LastError == "" {
+		t.Errorf("status after a failed flush is %+v, want 4 queued and the error", status)
+	}
+	stub.set(func(s *rpcStub) { s.failSend = false })
+	if _, err := o.Flush(ctx); !errors.Is(err, metering.ErrMintPending) {
+		t.Fatalf("flush before the failed mint expired: %v, want %v", err, metering.ErrMintPending)
+	}
+
+	stub.set(func(s *rpcStub) { s.height += 1000 })
+	report, err := o.Flush(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Batches) != 1 || report.Batches[0].Tokens != 2 || report.Batches[0].Signature == nil {
+		t.Fatalf("flush minted %+v, want one batch of 2 tokens", report.Batches)
+	}
+	if stub.sent() != 1 {
+		t.Errorf("%d transactions sent, want 1", stub.sent())
+	}
+	status := o.Status()
+	if status.Queued != 0 || status.Tokens != 2 || status.LastError != "" {
+		t.Errorf("status after flush is %+v, want nothing queued and 2 tokens minted", status)
+	}
+	if end := start.Add(4 * testInterval); !status.Minted["roof-1"].Equal(end) {
+		t.Errorf("roof-1 minted until %s, want %s", status.Minted["roof-1"], end)
+	}
+
+	// Minted readings are duplicates, and less than a token is carried
+	// without sending a transaction
+	result, err := meter.Submit(ctx, server.URL, readings(t, meter, start, 5)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Accepted != 1 || result.Duplicates != 4 {
+		t.Errorf("resubmit result is %+v, want 1 accepted and 4 duplicates", *result)
+	}
+	report, err = o.Flush(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Batches) != 1 || report.Batches[0].Tokens != 0 || report.Batches[0].Carry != 500*zonnegosdk.WattHour {
+		t.Errorf("flush minted %+v, want one batch carrying 500Wh", report.Batches)
+	}
+	if stub.sent() != 1 {
+		t.Errorf("%d transactions sent, want 1", stub.sent())
+	}
+	if queued := o.Status().Queued; queued != 0 {
+		t.Errorf("%d readings queued after the carry, want 0", queued)
+	}
+}
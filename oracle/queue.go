@@ -0,0 +1,118 @@
+package oracle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/akbariandev/zonnegosdk/metering"
+)
+
+// queue is an append-only JSON lines file of the readings accepted but not
+// minted yet. It is not safe for concurrent use.
+type queue struct {
+	path     string
+	file     *os.File
+	readings []metering.Reading
+}
+
+// openQueue opens the queue file, creating it if needed. A final line left
+// incomplete by a crash during append is dropped.
+func openQueue(path string) (*queue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read queue: %w", err)
+	}
+
+	q := &queue{path: path}
+	complete := data[:bytes.LastIndexByte(data, '\n')+1]
+	for i, line := range bytes.Split(bytes.TrimSuffix(complete, []byte("\n")), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var reading metering.Reading
+		if err := json.Unmarshal(line, &reading); err != nil {
+			return nil, fmt.Errorf("failed to decode queue line %d: %w", i+1, err)
+		}
+		q.readings = append(q.readings, reading)
+	}
+	if len(complete) < len(data) {
+		if err := os.Truncate(path, int64(len(complete))); err != nil {
+			return nil, fmt.Errorf("failed to truncate queue: %w", err)
+		}
+	}
+
+	if q.file, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to open queue: %w", err)
+	}
+	return q, nil
+}
+
+// append durably adds the readings to the queue
+func (q *queue) append(readings []metering.Reading) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, reading := range readings {
+		if err := encoder.Encode(reading); err != nil {
+			return fmt.Errorf("failed to encode reading: %w", err)
+		}
+	}
+	if _, err := q.file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write queue: %w", err)
+	}
+	if err := q.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync queue: %w", err)
+	}
+	q.readings = append(q.readings, readings...)
+	return nil
+}
+
+// snapshot returns a copy of the queued readings
+func (q *queue) snapshot() []metering.Reading {
+	return append([]metering.Reading(nil), q.readings...)
+}
+
+// len returns the number of queued readings
+func (q *queue) len() int {
+	return len(q.readings)
+}
+
+// retain rewrites the queue with only the readings keep returns true for.
+// The new file replaces the old one atomically.
+func (q *queue) retain(keep func(metering.Reading) bool) error {
+	var kept []metering.Reading
+	for _, reading := range q.readings {
+		if keep(reading) {
+			kept = append(kept, reading)
+		}
+	}
+	if len(kept) == len(q.readings) {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, reading := range kept {
+		if err := encoder.Encode(reading); err != nil {
+			return fmt.Errorf("failed to encode reading: %w", err)
+		}
+	}
+	if err := metering.WriteFileAtomic(q.path, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to rewrite queue: %w", err)
+	}
+
+	file, err := os.OpenFile(q.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open queue: %w", err)
+	}
+	q.file.Close()
+	q.file = file
+	q.readings = kept
+	return nil
+}
+
+// close closes the queue file
+func (q *queue) close() error {
+	return q.file.Close()
+}
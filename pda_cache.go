@@ -0,0 +1,158 @@
+package zonnegosdk
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// DefaultPDACacheSize is the capacity of the PDA cache created by NewClient
+const DefaultPDACacheSize = 4096
+
+// PDACache is a concurrency-safe, bounded LRU cache of derived program
+// addresses and their bumps, keyed by seed bytes and program ID
+type PDACache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type pdaCacheEntry struct {
+	key     string
+	address solana.PublicKey
+	bump    uint8
+}
+
+// NewPDACache creates a PDA cache holding at most capacity entries
+func NewPDACache(capacity int) *PDACache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &PDACache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element, capacity),
+	}
+}
+
+// WithPDACache sets the cache used for PDA derivation. Passing nil disables caching.
+func WithPDACache(cache *PDACache) ClientOption {
+	return func(c *Client) {
+		c.pdaCache = cache
+	}
+}
+
+// Len returns the number of cached addresses
+func (p *PDACache) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.order.Len()
+}
+
+// Purge removes every cached address
+func (p *PDACache) Purge() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.order.Init()
+	p.entries = make(map[string]*list.Element, p.capacity)
+}
+
+// FindProgramAddress returns the cached address and bump for the seeds,
+// deriving and caching them on a miss
+func (p *PDACache) FindProgramAddress(seeds [][]byte, programID solana.PublicKey) (solana.PublicKey, uint8, error) {
+	key := pdaCacheKey(seeds, programID)
+	if address, bump, ok := p.get(key); ok {
+		return address, bump, nil
+	}
+
+	address, bump, err := solana.FindProgramAddress(seeds, programID)
+	if err != nil {
+		return solana.PublicKey{}, 0, err
+	}
+
+	p.put(key, address, bump)
+	return address, bump, nil
+}
+
+// CreateProgramAddress returns the address for seeds with an already known
+// bump, skipping the bump search. A cached entry is used when its bump matches.
+func (p *PDACache) CreateProgramAddress(seeds [][]byte, bump uint8, programID solana.PublicKey) (solana.PublicKey, error) {
+	key := pdaCacheKey(seeds, programID)
+	if address, cachedBump, ok := p.get(key); ok && cachedBump == bump {
+		return address, nil
+	}
+
+	return solana.CreateProgramAddress(withBump(seeds, bump), programID)
+}
+
+func (p *PDACache) get(key string) (solana.PublicKey, uint8, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	element, ok := p.entries[key]
+	if !ok {
+		return solana.PublicKey{}, 0, false
+	}
+	p.order.MoveToFront(element)
+	entry := element.Value.(*pdaCacheEntry)
+	return entry.address, entry.bump, true
+}
+
+func (p *PDACache) put(key string, address solana.PublicKey, bump uint8) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if element, ok := p.entries[key]; ok {
+		p.order.MoveToFront(element)
+		return
+	}
+
+	p.entries[key] = p.order.PushFront(&pdaCacheEntry{key: key, address: address, bump: bump})
+	for p.order.Len() > p.capacity {
+		oldest := p.order.Back()
+		p.order.Remove(oldest)
+		delete(p.entries, oldest.Value.(*pdaCacheEntry).key)
+	}
+}
+
+// withBump returns a copy of the seeds with the bump appended
+func withBump(seeds [][]byte, bump uint8) [][]byte {
+	out := make([][]byte, 0, len(seeds)+1)
+	out = append(out, seeds...)
+	return append(out, []byte{bump})
+}
+
+// pdaCacheKey encodes the program ID and length-prefixed seeds so that
+// different seed splits never share a key
+func pdaCacheKey(seeds [][]byte, programID solana.PublicKey) string {
+	size := solana.PublicKeyLength
+	for _, seed := range seeds {
+		size += 1 + len(seed)
+	}
+
+	key := make([]byte, 0, size)
+	key = append(key, programID[:]...)
+	for _, seed := range seeds {
+		key = append(key, byte(len(seed)))
+		key = append(key, seed...)
+	}
+	return string(key)
+}
+
+// findProgramAddress derives a PDA of the client's program, using the cache when enabled
+func (c *Client) findProgramAddress(seeds [][]byte) (solana.PublicKey, uint8, error) {
+	if c.pdaCache == nil {
+		return solana.FindProgramAddress(seeds, c.programID)
+	}
+	return c.pdaCache.FindProgramAddress(seeds, c.programID)
+}
+
+// createProgramAddress computes a PDA of the client's program from seeds and a known bump
+func (c *Client) createProgramAddress(seeds [][]byte, bump uint8) (solana.PublicKey, error) {
+	if c.pdaCache == nil {
+		return solana.CreateProgramAddress(withBump(seeds, bump), c.programID)
+	}
+	return c.pdaCache.CreateProgramAddress(seeds, bump, c.programID)
+}
@@ -0,0 +1,281 @@
+package zonnegosdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProgramID is the address of the Zonne program on localnet, devnet and testnet
+const DefaultProgramID = "Aw4Ef9sT3VBv7FXo1qWYR4CQN7LDuTkCcQQC3mxrjwab"
+
+// Built-in profile names
+const (
+	ProfileLocalnet = "localnet"
+	ProfileDevnet   = "devnet"
+	ProfileTestnet  = "testnet"
+	ProfileMainnet  = "mainnet"
+)
+
+// Environment variables read by LoadProfile
+const (
+	EnvProfile         = "ZONNE_PROFILE"
+	EnvRPCURL          = "ZONNE_RPC_URL"
+	EnvSolanaRPCURL    = "SOLANA_RPC_URL"
+	EnvWebsocketURL    = "ZONNE_WS_URL"
+	EnvProgramID       = "ZONNE_PROGRAM_ID"
+	EnvCommitment      = "ZONNE_COMMITMENT"
+	EnvFeePayerKeypair = "ZONNE_FEE_PAYER_KEYPAIR"
+)
+
+// Profile bundles the settings needed to connect to a Zonne deployment
+type Profile struct {
+	Name            string             `json:"name" yaml:"name" toml:"name"`
+	RPCURL          string             `json:"rpc_url" yaml:"rpc_url" toml:"rpc_url"`
+	WebsocketURL    string             `json:"websocket_url,omitempty" yaml:"websocket_url,omitempty" toml:"websocket_url,omitempty"`
+	ProgramID       string             `json:"program_id" yaml:"program_id" toml:"program_id"`
+	Commitment      rpc.CommitmentType `json:"commitment,omitempty" yaml:"commitment,omitempty" toml:"commitment,omitempty"`
+	FeePayerKeypair string             `json:"fee_payer_keypair,omitempty" yaml:"fee_payer_keypair,omitempty" toml:"fee_payer_keypair,omitempty"`
+}
+
+// ProfileConfig is the content of a profile config file
+type ProfileConfig struct {
+	Default  string             `json:"default" yaml:"default" toml:"default"`
+	Profiles map[string]Profile `json:"profiles" yaml:"profiles" toml:"profiles"`
+}
+
+// builtinProfiles returns the profiles of the public clusters
+func builtinProfiles() map[string]Profile {
+	return map[string]Profile{
+		ProfileLocalnet: {Name: ProfileLocalnet, RPCURL: LocalnetRPC, ProgramID: DefaultProgramID, Commitment: rpc.CommitmentConfirmed},
+		ProfileDevnet:   {Name: ProfileDevnet, RPCURL: DevnetRPC, ProgramID: DefaultProgramID, Commitment: rpc.CommitmentConfirmed},
+		ProfileTestnet:  {Name: ProfileTestnet, RPCURL: TestnetRPC, ProgramID: DefaultProgramID, Commitment: rpc.CommitmentConfirmed},
+		ProfileMainnet:  {Name: ProfileMainnet, RPCURL: MainnetRPC, Commitment: rpc.CommitmentFinalized},
+	}
+}
+
+// BuiltinProfile returns the built-in profile with the given name. The
+// mainnet profile has no program ID; set one before use.
+func BuiltinProfile(name string) (Profile, error) {
+	profile, ok := builtinProfiles()[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile %q", name)
+	}
+	return profile, nil
+}
+
+// LocalnetProfile returns the built-in localnet profile
+func LocalnetProfile() Profile {
+	return builtinProfiles()[ProfileLocalnet]
+}
+
+// DevnetProfile returns the built-in devnet profile
+func DevnetProfile() Profile {
+	return builtinProfiles()[ProfileDevnet]
+}
+
+// Validate checks that the profile can be used to create a client
+func (p Profile) Validate() error {
+	v := newValidator("profile " + p.Name)
+	if p.RPCURL == "" {
+		v.add("RPCURL", "must be set")
+	}
+	if p.ProgramID == "" {
+		v.add("ProgramID", "must be set")
+	} else if _, err := solana.PublicKeyFromBase58(p.ProgramID); err != nil {
+		v.add("ProgramID", "%v", err)
+	}
+	switch p.Commitment {
+	case "", rpc.CommitmentProcessed, rpc.CommitmentConfirmed, rpc.CommitmentFinalized:
+	default:
+		v.add("Commitment", "must be processed, confirmed or finalized, got %q", p.Commitment)
+	}
+	return v.err()
+}
+
+// LoadFeePayer reads the profile's fee payer keypair file
+func (p Profile) LoadFeePayer() (solana.PrivateKey, error) {
+	if p.FeePayerKeypair == "" {
+		return nil, fmt.Errorf("profile %s has no fee payer keypair", p.Name)
+	}
+	key, err := solana.PrivateKeyFromSolanaKeygenFile(expandHome(p.FeePayerKeypair))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fee payer keypair: %w", err)
+	}
+	return key, nil
+}
+
+// merge overrides the profile's settings with the non-empty settings of other
+func (p Profile) merge(other Profile) Profile {
+	if other.Name != "" {
+		p.Name = other.Name
+	}
+	if other.RPCURL != "" {
+		p.RPCURL = other.RPCURL
+	}
+	if other.WebsocketURL != "" {
+		p.WebsocketURL = other.WebsocketURL
+	}
+	if other.ProgramID != "" {
+		p.ProgramID = other.ProgramID
+	}
+	if other.Commitment != "" {
+		p.Commitment = other.Commitment
+	}
+	if other.FeePayerKeypair != "" {
+		p.FeePayerKeypair = other.FeePayerKeypair
+	}
+	return p
+}
+
+// ParseProfileConfig parses a profile config in the given format: "yaml",
+// "toml" or "json"
+func ParseProfileConfig(data []byte, format string) (*ProfileConfig, error) {
+	var config ProfileConfig
+	var err error
+	switch format {
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &config)
+	case "toml":
+		err = toml.Unmarshal(data, &config)
+	case "json":
+		err = json.Unmarshal(data, &config)
+	default:
+		return nil, fmt.Errorf("unsupported profile config format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s profile config: %w", format, err)
+	}
+	return &config, nil
+}
+
+// LoadProfileConfig reads a profile config file, choosing the format from
+// its extension (.yaml, .yml, .toml or .json)
+func LoadProfileConfig(path string) (*ProfileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile config: %w", err)
+	}
+	return ParseProfileConfig(data, strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// Profile returns the named profile, layered over the built-in profile of
+// the same name if there is one. An empty name selects the config's default.
+func (c *ProfileConfig) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = c.Default
+	}
+	if name == "" {
+		name = ProfileLocalnet
+	}
+
+	builtin, isBuiltin := builtinProfiles()[name]
+	configured, isConfigured := c.Profiles[name]
+	if !isBuiltin && !isConfigured {
+		return Profile{}, fmt.Errorf("unknown profile %q, available: %s", name, strings.Join(c.Names(), ", "))
+	}
+
+	profile := builtin.merge(configured)
+	profile.Name = name
+	return profile, nil
+}
+
+// Names returns the names of the built-in and configured profiles
+func (c *ProfileConfig) Names() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for name := range builtinProfiles() {
+		seen[name] = true
+		names = append(names, name)
+	}
+	for name := range c.Profiles {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyEnv overrides the profile with the ZONNE_* environment variables.
+// SOLANA_RPC_URL is used when ZONNE_RPC_URL is not set.
+func (p Profile) ApplyEnv() Profile {
+	rpcURL := os.Getenv(EnvRPCURL)
+	if rpcURL == "" {
+		rpcURL = os.Getenv(EnvSolanaRPCURL)
+	}
+	return p.merge(Profile{
+		RPCURL:          rpcURL,
+		WebsocketURL:    os.Getenv(EnvWebsocketURL),
+		ProgramID:       os.Getenv(EnvProgramID),
+		Commitment:      rpc.CommitmentType(os.Getenv(EnvCommitment)),
+		FeePayerKeypair: os.Getenv(EnvFeePayerKeypair),
+	})
+}
+
+// LoadProfile resolves a profile from the built-in profiles, an optional
+// config file and the environment. An empty name selects ZONNE_PROFILE, then
+// the config file's default, then localnet. Environment variables override
+// the settings of the selected profile.
+func LoadProfile(configPath, name string) (Profile, error) {
+	config := &ProfileConfig{}
+	if configPath != "" {
+		var err error
+		if config, err = LoadProfileConfig(configPath); err != nil {
+			return Profile{}, err
+		}
+	}
+
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+
+	profile, err := config.Profile(name)
+	if err != nil {
+		return Profile{}, err
+	}
+	profile = profile.ApplyEnv()
+	profile.FeePayerKeypair = expandHome(profile.FeePayerKeypair)
+
+	return profile, profile.Validate()
+}
+
+// NewClientFromProfile creates a client from a profile. It returns an error
+// instead of panicking when the profile is incomplete or invalid.
+func NewClientFromProfile(profile Profile, opts ...ClientOption) (*Client, error) {
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+
+	programID := solana.MustPublicKeyFromBase58(profile.ProgramID)
+
+	var profileOpts []ClientOption
+	if profile.WebsocketURL != "" {
+		profileOpts = append(profileOpts, WithWebsocketEndpoint(profile.WebsocketURL))
+	}
+	if profile.Commitment != "" {
+		profileOpts = append(profileOpts, WithCommitment(profile.Commitment))
+	}
+
+	return newClient(profile.RPCURL, programID, append(profileOpts, opts...))
+}
+
+// expandHome replaces a leading ~ in a path with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
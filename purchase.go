@@ -0,0 +1,228 @@
+package zonnegosdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// BuyTokens and MintEnergyTokens require the buyer's consumer account and
+// the producer's account to exist. The flows below check for them and
+// prepend the initialization in the same transaction when they are missing,
+// so first-time participants need no separate setup step.
+
+// PurchasePlan is the instructions that buy a listing
+type PurchasePlan struct {
+	Instructions []solana.Instruction
+	// InitializesConsumer reports whether InitializeConsumer was prepended
+	InitializesConsumer bool
+}
+
+// PlanPurchase builds the instructions that buy a listing, prepending
+// InitializeConsumer with consumerAuthority when the buyer has no consumer
+// account yet
+func (c *Client) PlanPurchase(ctx context.Context, buyer solana.PublicKey, ref ListingRef, consumerAuthority solana.PublicKey) (*PurchasePlan, error) {
+	plan := &PurchasePlan{}
+
+	exists, err := c.ConsumerAccountExists(ctx, buyer)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		instruction, err := c.InitializeConsumer(ConsumerAccountCreationParams{Consumer: buyer, Authority: consumerAuthority})
+		if err != nil {
+			return nil, err
+		}
+		plan.Instructions = append(plan.Instructions, instruction)
+		plan.InitializesConsumer = true
+	}
+
+	instruction, err := c.BuyTokensFromListing(buyer, ref)
+	if err != nil {
+		return nil, err
+	}
+	plan.Instructions = append(plan.Instructions, instruction)
+
+	return plan, nil
+}
+
+// PurchaseParams holds parameters for Purchase
+type PurchaseParams struct {
+	// Buyer signs the purchase and pays the transaction fees
+	Buyer   solana.PrivateKey
+	Listing ListingRef
+	// ConsumerAuthority initializes the buyer's consumer account if it is
+	// missing and pays its rent. Defaults to Buyer.
+	ConsumerAuthority solana.PrivateKey
+}
+
+// PurchaseResult is the outcome of Purchase
+type PurchaseResult struct {
+	Signature           solana.Signature
+	InitializedConsumer bool
+}
+
+// Purchase buys a listing, initializing the buyer's consumer account in the
+// same transaction when it does not exist yet, and waits for confirmation
+func (c *Client) Purchase(ctx context.Context, params PurchaseParams) (*PurchaseResult, error) {
+	v := newValidator("purchase")
+	v.privateKey("Buyer", params.Buyer)
+	if params.ConsumerAuthority != nil {
+		v.privateKey("ConsumerAuthority", params.ConsumerAuthority)
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	authority := params.ConsumerAuthority
+	if authority == nil {
+		authority = params.Buyer
+	}
+
+	plan, err := c.PlanPurchase(ctx, params.Buyer.PublicKey(), params.Listing, authority.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+
+	signers := []solana.PrivateKey{params.Buyer}
+	if plan.InitializesConsumer && !authority.PublicKey().Equals(params.Buyer.PublicKey()) {
+		signers = append(signers, authority)
+	}
+
+	signature, err := c.sendInstructions(ctx, params.Buyer.PublicKey(), signers, plan.Instructions)
+	if err != nil {
+		return nil, err
+	}
+	return &PurchaseResult{Signature: signature, InitializedConsumer: plan.InitializesConsumer}, nil
+}
+
+// MintPlan is the instructions that mint energy tokens to a producer
+type MintPlan struct {
+	Instructions []solana.Instruction
+	// Mints are the mint records created, more than one when the strategy
+	// split the amount to avoid a seed collision
+	Mints []PlannedMint
+	// InitializesProducer reports whether InitializeProducer was prepended
+	InitializesProducer bool
+}
+
+// PlanMintWithProducer builds the instructions that mint energy tokens,
+// prepending InitializeProducer with producerAuthority when the producer has
+// no producer account yet. Seed collisions are resolved as in PlanMint.
+func (c *Client) PlanMintWithProducer(ctx context.Context, params MintRecordCreationParams, producerAuthority solana.PublicKey, strategy CollisionStrategy) (*MintPlan, error) {
+	plan := &MintPlan{}
+
+	exists, err := c.ProducerAccountExists(ctx, params.Producer)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		instruction, err := c.InitializeProducer(ProducerAccountCreationParams{Producer: params.Producer, Authority: producerAuthority})
+		if err != nil {
+			return nil, err
+		}
+		plan.Instructions = append(plan.Instructions, instruction)
+		plan.InitializesProducer = true
+	}
+
+	mints, err := c.PlanMint(ctx, params, strategy)
+	if err != nil {
+		return nil, err
+	}
+	for _, mint := range mints {
+		plan.Instructions = append(plan.Instructions, mint.Instruction)
+	}
+	plan.Mints = mints
+
+	return plan, nil
+}
+
+// MintTokensParams holds parameters for MintTokens
+type MintTokensParams struct {
+	Grid     solana.PublicKey
+	Producer solana.PublicKey
+	// Energy is the energy to mint and must be a whole number of tokens.
+	// Set either Energy or Amount.
+	Energy Energy
+	// Amount is a number of tokens of TokenUnit (1 kWh) each
+	Amount     uint64
+	EnergyType EnergyType
+	// GridAuthority signs the mint and pays the transaction fees
+	GridAuthority solana.PrivateKey
+	// ProducerAuthority initializes the producer account if it is missing
+	// and pays its rent. Defaults to GridAuthority.
+	ProducerAuthority solana.PrivateKey
+	// Strategy resolves mint record seed collisions; the zero value fails
+	Strategy CollisionStrategy
+}
+
+// MintTokensResult is the outcome of MintTokens
+type MintTokensResult struct {
+	Signature           solana.Signature
+	Mints               []PlannedMint
+	InitializedProducer bool
+}
+
+// MintTokens mints energy tokens to a producer, initializing the producer
+// account in the same transaction when it does not exist yet, and waits for
+// confirmation
+func (c *Client) MintTokens(ctx context.Context, params MintTokensParams) (*MintTokensResult, error) {
+	v := newValidator("mint tokens")
+	v.privateKey("GridAuthority", params.GridAuthority)
+	if params.ProducerAuthority != nil {
+		v.privateKey("ProducerAuthority", params.ProducerAuthority)
+	}
+	amount := params.Amount
+	if params.Energy != 0 {
+		tokens, err := params.Energy.Tokens()
+		switch {
+		case params.Amount != 0:
+			v.add("Energy", "cannot be set together with Amount")
+		case err != nil:
+			v.add("Energy", "%v", err)
+		default:
+			amount = tokens
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	authority := params.ProducerAuthority
+	if authority == nil {
+		authority = params.GridAuthority
+	}
+
+	plan, err := c.PlanMintWithProducer(ctx, MintRecordCreationParams{
+		Grid:          params.Grid,
+		Producer:      params.Producer,
+		Amount:        amount,
+		EnergyType:    params.EnergyType,
+		GridAuthority: params.GridAuthority.PublicKey(),
+	}, authority.PublicKey(), params.Strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	signers := []solana.PrivateKey{params.GridAuthority}
+	if plan.InitializesProducer && !authority.PublicKey().Equals(params.GridAuthority.PublicKey()) {
+		signers = append(signers, authority)
+	}
+
+	signature, err := c.sendInstructions(ctx, params.GridAuthority.PublicKey(), signers, plan.Instructions)
+	if err != nil {
+		return nil, err
+	}
+	return &MintTokensResult{Signature: signature, Mints: plan.Mints, InitializedProducer: plan.InitializesProducer}, nil
+}
+
+// sendInstructions sends the instructions in one transaction paid for by
+// payer and waits for confirmation
+func (c *Client) sendInstructions(ctx context.Context, payer solana.PublicKey, signers []solana.PrivateKey, instructions []solana.Instruction) (solana.Signature, error) {
+	transaction, err := solana.NewTransaction(instructions, solana.Hash{}, solana.TransactionPayer(payer))
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	return c.SendAndConfirmTransaction(ctx, transaction, signers)
+}
@@ -0,0 +1,660 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	"github.com/mr-tron/base58"
+)
+
+// Transaction encodings accepted by the transaction endpoints
+const (
+	EncodingBase64 = "base64"
+	EncodingBase58 = "base58"
+)
+
+// GridResponse is the state of a grid account
+type GridResponse struct {
+	Grid     solana.PublicKey `json:"grid"`
+	Address  solana.PublicKey `json:"address"`
+	IsActive bool             `json:"is_active"`
+}
+
+// ProducerResponse is the state of a producer account
+type ProducerResponse struct {
+	Producer      solana.PublicKey  `json:"producer"`
+	Address       solana.PublicKey  `json:"address"`
+	Balance       uint64            `json:"balance"`
+	BalanceEnergy zonnegosdk.Energy `json:"balance_energy"`
+}
+
+// ConsumerResponse is the state of a consumer account
+type ConsumerResponse struct {
+	Consumer          solana.PublicKey  `json:"consumer"`
+	Address           solana.PublicKey  `json:"address"`
+	Consumption       uint64            `json:"consumption"`
+	ConsumptionEnergy zonnegosdk.Energy `json:"consumption_energy"`
+}
+
+// ListingResponse is the state of a listing account. Ref identifies the
+// listing in the buy and cancel requests.
+type ListingResponse struct {
+	Ref           string                `json:"ref"`
+	Address       solana.PublicKey      `json:"address"`
+	Producer      solana.PublicKey      `json:"producer"`
+	Amount        uint64                `json:"amount"`
+	Energy        zonnegosdk.Energy     `json:"energy"`
+	PriceLamports uint64                `json:"price_lamports"`
+	PriceSOL      string                `json:"price_sol"`
+	EnergyType    zonnegosdk.EnergyType `json:"energy_type"`
+	IsActive      bool                  `json:"is_active"`
+	CreatedAt     time.Time             `json:"created_at"`
+}
+
+// MintRecordResponse is the state of a mint record account
+type MintRecordResponse struct {
+	Address    solana.PublicKey      `json:"address"`
+	Grid       solana.PublicKey      `json:"grid"`
+	Producer   solana.PublicKey      `json:"producer"`
+	Amount     uint64                `json:"amount"`
+	Energy     zonnegosdk.Energy     `json:"energy"`
+	EnergyType zonnegosdk.EnergyType `json:"energy_type"`
+	Timestamp  time.Time             `json:"timestamp"`
+}
+
+// OrderBookResponse lists the active listings, cheapest per token first
+type OrderBookResponse struct {
+	Listings []ListingResponse `json:"listings"`
+}
+
+// MintRequest asks for an unsigned mint_energy_tokens transaction
+type MintRequest struct {
+	Grid          solana.PublicKey       `json:"grid"`
+	Producer      solana.PublicKey       `json:"producer"`
+	GridAuthority solana.PublicKey       `json:"grid_authority"`
+	Amount        uint64                 `json:"amount"`
+	EnergyType    *zonnegosdk.EnergyType `json:"energy_type"`
+	FeePayer      *solana.PublicKey      `json:"fee_payer,omitempty"`
+	Encoding      string                 `json:"encoding,omitempty" enum:"base64,base58"`
+}
+
+// ListRequest asks for an unsigned list_tokens_for_sale transaction
+type ListRequest struct {
+	Producer      solana.PublicKey       `json:"producer"`
+	Amount        uint64                 `json:"amount"`
+	PriceLamports uint64                 `json:"price_lamports"`
+	EnergyType    *zonnegosdk.EnergyType `json:"energy_type"`
+	FeePayer      *solana.PublicKey      `json:"fee_payer,omitempty"`
+	Encoding      string                 `json:"encoding,omitempty" enum:"base64,base58"`
+}
+
+// BuyRequest asks for an unsigned buy_tokens transaction
+type BuyRequest struct {
+	Buyer    solana.PublicKey  `json:"buyer"`
+	Listing  string            `json:"listing"`
+	FeePayer *solana.PublicKey `json:"fee_payer,omitempty"`
+	Encoding string            `json:"encoding,omitempty" enum:"base64,base58"`
+}
+
+// CancelRequest asks for an unsigned cancel_listing transaction
+type CancelRequest struct {
+	Listing  string            `json:"listing"`
+	FeePayer *solana.PublicKey `json:"fee_payer,omitempty"`
+	Encoding string            `json:"encoding,omitempty" enum:"base64,base58"`
+}
+
+// UnsignedTransactionResponse is a transaction to be signed by Signers and
+// submitted before LastValidBlockHeight. Listing is the reference of the
+// listing a list transaction creates.
+type UnsignedTransactionResponse struct {
+	Transaction          string             `json:"transaction"`
+	Encoding             string             `json:"encoding"`
+	Blockhash            solana.Hash        `json:"blockhash"`
+	LastValidBlockHeight uint64             `json:"last_valid_block_height"`
+	FeePayer             solana.PublicKey   `json:"fee_payer"`
+	Signers              []solana.PublicKey `json:"signers"`
+	Listing              string             `json:"listing,omitempty"`
+}
+
+// SubmitRequest sends a signed transaction. With Confirm, the response is
+// written once the transaction is confirmed.
+type SubmitRequest struct {
+	Transaction string `json:"transaction"`
+	Encoding    string `json:"encoding,omitempty" enum:"base64,base58"`
+	Confirm     bool   `json:"confirm,omitempty"`
+}
+
+// SubmitResponse is the signature of a submitted transaction
+type SubmitResponse struct {
+	Signature solana.Signature `json:"signature"`
+	Confirmed bool             `json:"confirmed"`
+}
+
+// specPath is the path of the OpenAPI document
+const specPath = "/openapi.json"
+
+// routeTable lists every API endpoint
+func (s *Server) routeTable() []route {
+	encodingParam := "base64 (default) or base58"
+	return []route{
+		{
+			method: http.MethodGet, path: "/v1/grids/{grid}", operationID: "getGrid",
+			summary:  "Get a grid account",
+			params:   []param{pathParam("grid", "grid public key", solana.PublicKey{})},
+			response: GridResponse{},
+			handle:   s.getGrid,
+		},
+		{
+			method: http.MethodGet, path: "/v1/producers/{producer}", operationID: "getProducer",
+			summary:  "Get a producer account",
+			params:   []param{pathParam("producer", "producer wallet", solana.PublicKey{})},
+			response: ProducerResponse{},
+			handle:   s.getProducer,
+		},
+		{
+			method: http.MethodGet, path: "/v1/producers/{producer}/mint-records", operationID: "getMintRecord",
+			summary: "Get the mint record of a producer for an amount and energy type",
+			params: []param{
+				pathParam("producer", "producer wallet", solana.PublicKey{}),
+				queryParam("amount", "minted token amount", true, uint64(0)),
+				queryParam("energy_type", "energy type", true, zonnegosdk.EnergyType(0)),
+			},
+			response: MintRecordResponse{},
+			handle:   s.getMintRecord,
+		},
+		{
+			method: http.MethodGet, path: "/v1/consumers/{consumer}", operationID: "getConsumer",
+			summary:  "Get a consumer account",
+			params:   []param{pathParam("consumer", "consumer wallet", solana.PublicKey{})},
+			response: ConsumerResponse{},
+			handle:   s.getConsumer,
+		},
+		{
+			method: http.MethodGet, path: "/v1/listings/{listing}", operationID: "getListing",
+			summary:  "Get a listing account by reference",
+			params:   []param{pathParam("listing", "listing reference", "")},
+			response: ListingResponse{},
+			handle:   s.getListing,
+		},
+		{
+			method: http.MethodGet, path: "/v1/orderbook", operationID: "getOrderBook",
+			summary: "List active listings, cheapest per token first",
+			params: []param{
+				queryParam("energy_type", "only listings of this energy type", false, zonnegosdk.EnergyType(0)),
+				queryParam("producer", "only listings of this producer", false, solana.PublicKey{}),
+			},
+			response: OrderBookResponse{},
+			handle:   s.getOrderBook,
+		},
+		{
+			method: http.MethodPost, path: "/v1/transactions/mint", operationID: "buildMintTransaction",
+			summary:  "Build an unsigned transaction minting energy tokens, initializing the producer account if missing, signed by the grid authority; encoding is " + encodingParam,
+			body:     MintRequest{},
+			response: UnsignedTransactionResponse{},
+			handle:   s.buildMint,
+		},
+		{
+			method: http.MethodPost, path: "/v1/transactions/list", operationID: "buildListTransaction",
+			summary:  "Build an unsigned transaction listing energy tokens for sale, signed by the producer; encoding is " + encodingParam,
+			body:     ListRequest{},
+			response: UnsignedTransactionResponse{},
+			handle:   s.buildList,
+		},
+		{
+			method: http.MethodPost, path: "/v1/transactions/buy", operationID: "buildBuyTransaction",
+			summary:  "Build an unsigned transaction buying a listing, initializing the buyer's consumer account if missing, signed by the buyer; encoding is " + encodingParam,
+			body:     BuyRequest{},
+			response: UnsignedTransactionResponse{},
+			handle:   s.buildBuy,
+		},
+		{
+			method: http.MethodPost, path: "/v1/transactions/cancel", operationID: "buildCancelTransaction",
+			summary:  "Build an unsigned transaction cancelling a listing, signed by the producer; encoding is " + encodingParam,
+			body:     CancelRequest{},
+			response: UnsignedTransactionResponse{},
+			handle:   s.buildCancel,
+		},
+		{
+			method: http.MethodPost, path: "/v1/transactions/submit", operationID: "submitTransaction",
+			summary:  "Submit a signed transaction that calls the Zonne program; encoding is " + encodingParam,
+			body:     SubmitRequest{},
+			response: SubmitResponse{},
+			handle:   s.submit,
+		},
+	}
+}
+
+func (s *Server) getGrid(r *request) (interface{}, error) {
+	grid, err := publicKeyParam("grid", r.params["grid"])
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.client.GetGridAccount(r.Context(), grid)
+	if err != nil {
+		return nil, err
+	}
+	address, _, err := s.client.DeriveGridAccountPDA(grid)
+	if err != nil {
+		return nil, err
+	}
+	return GridResponse{Grid: grid, Address: address, IsActive: account.IsActive}, nil
+}
+
+func (s *Server) getProducer(r *request) (interface{}, error) {
+	producer, err := publicKeyParam("producer", r.params["producer"])
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.client.GetProducerAccount(r.Context(), producer)
+	if err != nil {
+		return nil, err
+	}
+	address, _, err := s.client.DeriveProducerAccountPDA(producer)
+	if err != nil {
+		return nil, err
+	}
+	energy, err := account.BalanceEnergy()
+	if err != nil {
+		return nil, err
+	}
+	return ProducerResponse{Producer: producer, Address: address, Balance: account.Balance, BalanceEnergy: energy}, nil
+}
+
+func (s *Server) getConsumer(r *request) (interface{}, error) {
+	consumer, err := publicKeyParam("consumer", r.params["consumer"])
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.client.GetConsumerAccount(r.Context(), consumer)
+	if err != nil {
+		return nil, err
+	}
+	address, _, err := s.client.DeriveConsumerAccountPDA(consumer)
+	if err != nil {
+		return nil, err
+	}
+	energy, err := account.ConsumptionEnergy()
+	if err != nil {
+		return nil, err
+	}
+	return ConsumerResponse{Consumer: consumer, Address: address, Consumption: account.Consumption, ConsumptionEnergy: energy}, nil
+}
+
+func (s *Server) getMintRecord(r *request) (interface{}, error) {
+	producer, err := publicKeyParam("producer", r.params["producer"])
+	if err != nil {
+		return nil, err
+	}
+	query := r.URL.Query()
+	amount, err := strconv.ParseUint(query.Get("amount"), 10, 64)
+	if err != nil {
+		return nil, badRequest("invalid amount %q", query.Get("amount"))
+	}
+	energyType, err := zonnegosdk.ParseEnergyTypeStrict(query.Get("energy_type"))
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+
+	record, err := s.client.GetMintRecord(r.Context(), producer, amount, energyType)
+	if err != nil {
+		return nil, err
+	}
+	address, _, err := s.client.DeriveMintRecordPDA(producer, amount, energyType)
+	if err != nil {
+		return nil, err
+	}
+	energy, err := record.Energy()
+	if err != nil {
+		return nil, err
+	}
+	return MintRecordResponse{
+		Address:    address,
+		Grid:       record.Grid,
+		Producer:   record.Producer,
+		Amount:     record.Amount,
+		Energy:     energy,
+		EnergyType: record.EnergyType,
+		Timestamp:  record.GetTimestamp().UTC(),
+	}, nil
+}
+
+func (s *Server) getListing(r *request) (interface{}, error) {
+	ref, err := s.listingRef(r.params["listing"])
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.client.GetListingAccountByRef(r.Context(), ref)
+	if err != nil {
+		return nil, err
+	}
+	return newListingResponse(ref, account)
+}
+
+func (s *Server) getOrderBook(r *request) (interface{}, error) {
+	query := r.URL.Query()
+	filter := zonnegosdk.ListingFilter{ActiveOnly: true}
+	if value := query.Get("producer"); value != "" {
+		producer, err := publicKeyParam("producer", value)
+		if err != nil {
+			return nil, err
+		}
+		filter.Producer = &producer
+	}
+	var energyType *zonnegosdk.EnergyType
+	if value := query.Get("energy_type"); value != "" {
+		parsed, err := zonnegosdk.ParseEnergyTypeStrict(value)
+		if err != nil {
+			return nil, badRequest("%v", err)
+		}
+		energyType = &parsed
+	}
+
+	listings, err := s.client.GetListings(r.Context(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	book := OrderBookResponse{Listings: []ListingResponse{}}
+	for i := range listings {
+		listing := &listings[i]
+		if energyType != nil && listing.Account.EnergyType != *energyType {
+			continue
+		}
+		response, err := newListingResponse(listing.Ref, &listing.Account)
+		if err != nil {
+			return nil, err
+		}
+		book.Listings = append(book.Listings, response)
+	}
+
+	sort.SliceStable(book.Listings, func(i, j int) bool {
+		a, b := book.Listings[i], book.Listings[j]
+		if cmp := compareUnitPrice(a, b); cmp != 0 {
+			return cmp < 0
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	return book, nil
+}
+
+// compareUnitPrice compares the per-token prices of two listings exactly
+func compareUnitPrice(a, b ListingResponse) int {
+	aHi, aLo := bits.Mul64(a.PriceLamports, b.Amount)
+	bHi, bLo := bits.Mul64(b.PriceLamports, a.Amount)
+	switch {
+	case aHi < bHi || (aHi == bHi && aLo < bLo):
+		return -1
+	case aHi > bHi || (aHi == bHi && aLo > bLo):
+		return 1
+	}
+	return 0
+}
+
+func (s *Server) buildMint(r *request) (interface{}, error) {
+	var body MintRequest
+	if err := r.decode(&body); err != nil {
+		return nil, err
+	}
+	if body.EnergyType == nil {
+		return nil, missingField(zonnegosdk.InstructionMintEnergyTokens, "EnergyType")
+	}
+
+	plan, err := s.client.PlanMintWithProducer(r.Context(), zonnegosdk.MintRecordCreationParams{
+		Grid:          body.Grid,
+		Producer:      body.Producer,
+		Amount:        body.Amount,
+		EnergyType:    *body.EnergyType,
+		GridAuthority: body.GridAuthority,
+	}, body.GridAuthority, zonnegosdk.CollisionFail)
+	if err != nil {
+		return nil, err
+	}
+	return s.unsignedTransaction(r.Context(), body.Encoding, body.GridAuthority, body.FeePayer, "", plan.Instructions...)
+}
+
+func (s *Server) buildList(r *request) (interface{}, error) {
+	var body ListRequest
+	if err := r.decode(&body); err != nil {
+		return nil, err
+	}
+	if body.EnergyType == nil {
+		return nil, missingField(zonnegosdk.InstructionListTokensForSale, "EnergyType")
+	}
+
+	planned, err := s.client.PlanListing(r.Context(), zonnegosdk.ListingAccountCreationParams{
+		Producer:      body.Producer,
+		Amount:        body.Amount,
+		PriceLamports: body.PriceLamports,
+		EnergyType:    *body.EnergyType,
+	}, zonnegosdk.CollisionFail)
+	if err != nil {
+		return nil, err
+	}
+	listing := planned[0]
+	return s.unsignedTransaction(r.Context(), body.Encoding, body.Producer, body.FeePayer, listing.Ref.String(), listing.Instruction)
+}
+
+func (s *Server) buildBuy(r *request) (interface{}, error) {
+	var body BuyRequest
+	if err := r.decode(&body); err != nil {
+		return nil, err
+	}
+	ref, err := s.activeListing(r.Context(), body.Listing)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.client.PlanPurchase(r.Context(), body.Buyer, ref, body.Buyer)
+	if err != nil {
+		return nil, err
+	}
+	return s.unsignedTransaction(r.Context(), body.Encoding, body.Buyer, body.FeePayer, "", plan.Instructions...)
+}
+
+func (s *Server) buildCancel(r *request) (interface{}, error) {
+	var body CancelRequest
+	if err := r.decode(&body); err != nil {
+		return nil, err
+	}
+	ref, err := s.activeListing(r.Context(), body.Listing)
+	if err != nil {
+		return nil, err
+	}
+
+	instruction, err := s.client.CancelListingByRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	return s.unsignedTransaction(r.Context(), body.Encoding, ref.Seeds.Producer, body.FeePayer, "", instruction)
+}
+
+func (s *Server) submit(r *request) (interface{}, error) {
+	var body SubmitRequest
+	if err := r.decode(&body); err != nil {
+		return nil, err
+	}
+
+	data, err := decodeTransaction(body.Transaction, body.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	transaction, err := solana.TransactionFromDecoder(bin.NewBinDecoder(data))
+	if err != nil {
+		return nil, badRequest("invalid transaction: %v", err)
+	}
+	if !s.callsProgram(transaction) {
+		return nil, badRequest("transaction does not call the Zonne program %s", s.client.GetProgramID())
+	}
+
+	signature, err := s.client.SubmitSignedTransaction(r.Context(), transaction)
+	if err != nil {
+		return nil, err
+	}
+	if !body.Confirm {
+		return SubmitResponse{Signature: signature}, nil
+	}
+	if err := s.client.ConfirmTransaction(r.Context(), signature); err != nil {
+		return nil, err
+	}
+	return SubmitResponse{Signature: signature, Confirmed: true}, nil
+}
+
+// unsignedTransaction builds and encodes a transaction paid for by feePayer,
+// or by signer when no fee payer is given
+func (s *Server) unsignedTransaction(ctx context.Context, encoding string, signer solana.PublicKey, feePayer *solana.PublicKey, listing string, instructions ...solana.Instruction) (*UnsignedTransactionResponse, error) {
+	encoding, err := checkEncoding(encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	payer := signer
+	if feePayer != nil {
+		if feePayer.IsZero() {
+			return nil, badRequest("invalid fee_payer: cannot be zero")
+		}
+		payer = *feePayer
+	}
+
+	transaction, lastValidBlockHeight, err := s.client.BuildUnsignedTransaction(ctx, payer, instructions...)
+	if err != nil {
+		return nil, err
+	}
+	data, err := transaction.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize transaction: %w", err)
+	}
+
+	numSigners := transaction.Message.Header.NumRequiredSignatures
+	return &UnsignedTransactionResponse{
+		Transaction:          encodeTransaction(data, encoding),
+		Encoding:             encoding,
+		Blockhash:            transaction.Message.RecentBlockhash,
+		LastValidBlockHeight: lastValidBlockHeight,
+		FeePayer:             payer,
+		Signers:              append([]solana.PublicKey(nil), transaction.Message.AccountKeys[:numSigners]...),
+		Listing:              listing,
+	}, nil
+}
+
+// callsProgram reports whether any instruction of the transaction calls the
+// client's program
+func (s *Server) callsProgram(transaction *solana.Transaction) bool {
+	for _, instruction := range transaction.Message.Instructions {
+		programID, err := transaction.ResolveProgramIDIndex(instruction.ProgramIDIndex)
+		if err == nil && programID.Equals(s.client.GetProgramID()) {
+			return true
+		}
+	}
+	return false
+}
+
+// listingRef parses and verifies a listing reference
+func (s *Server) listingRef(value string) (zonnegosdk.ListingRef, error) {
+	ref, err := zonnegosdk.ParseListingRef(value)
+	if err != nil {
+		return zonnegosdk.ListingRef{}, badRequest("%v", err)
+	}
+	if err := s.client.VerifyListingRef(ref); err != nil {
+		return zonnegosdk.ListingRef{}, badRequest("%v", err)
+	}
+	return ref, nil
+}
+
+// activeListing parses a listing reference and checks that the listing is
+// still open
+func (s *Server) activeListing(ctx context.Context, value string) (zonnegosdk.ListingRef, error) {
+	ref, err := s.listingRef(value)
+	if err != nil {
+		return zonnegosdk.ListingRef{}, err
+	}
+	account, err := s.client.GetListingAccountByRef(ctx, ref)
+	if err != nil {
+		return zonnegosdk.ListingRef{}, err
+	}
+	if !account.IsActive {
+		return zonnegosdk.ListingRef{}, &apiError{status: http.StatusConflict, message: "listing " + ref.Address.String() + " is not active"}
+	}
+	return ref, nil
+}
+
+// newListingResponse converts a listing account to its response
+func newListingResponse(ref zonnegosdk.ListingRef, account *zonnegosdk.ListingAccount) (ListingResponse, error) {
+	energy, err := account.Energy()
+	if err != nil {
+		return ListingResponse{}, err
+	}
+	return ListingResponse{
+		Ref:           ref.String(),
+		Address:       ref.Address,
+		Producer:      account.Producer,
+		Amount:        account.Amount,
+		Energy:        energy,
+		PriceLamports: account.PriceLamports,
+		PriceSOL:      zonnegosdk.FormatSOL(account.PriceLamports),
+		EnergyType:    account.EnergyType,
+		IsActive:      account.IsActive,
+		CreatedAt:     account.GetCreatedAt().UTC(),
+	}, nil
+}
+
+// publicKeyParam parses a public key parameter
+func publicKeyParam(name, value string) (solana.PublicKey, error) {
+	key, err := solana.PublicKeyFromBase58(value)
+	if err != nil {
+		return solana.PublicKey{}, badRequest("invalid %s %q: %v", name, value, err)
+	}
+	return key, nil
+}
+
+// missingField reports a required request field that was not set
+func missingField(op, field string) error {
+	return &zonnegosdk.ValidationError{Op: op, Fields: []zonnegosdk.FieldError{{Field: field, Message: "must be set"}}}
+}
+
+// checkEncoding validates a transaction encoding, defaulting to base64
+func checkEncoding(encoding string) (string, error) {
+	switch encoding {
+	case "":
+		return EncodingBase64, nil
+	case EncodingBase64, EncodingBase58:
+		return encoding, nil
+	}
+	return "", badRequest("invalid encoding %q: must be %s or %s", encoding, EncodingBase64, EncodingBase58)
+}
+
+// encodeTransaction encodes serialized transaction bytes
+func encodeTransaction(data []byte, encoding string) string {
+	if encoding == EncodingBase58 {
+		return base58.Encode(data)
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// decodeTransaction decodes an encoded transaction
+func decodeTransaction(value, encoding string) ([]byte, error) {
+	encoding, err := checkEncoding(encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if encoding == EncodingBase58 {
+		data, err = base58.Decode(value)
+	} else {
+		data, err = base64.StdEncoding.DecodeString(value)
+	}
+	if err != nil || len(data) == 0 {
+		return nil, badRequest("invalid %s transaction", encoding)
+	}
+	return data, nil
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/gagliardetto/solana-go"
+)
+
+// OpenAPIVersion is the OpenAPI version of the generated document
+const OpenAPIVersion = "3.0.3"
+
+// APIVersion is the version of the HTTP API
+const APIVersion = "1.0.0"
+
+// OpenAPI returns the OpenAPI document of the server's routes, ready to be
+// encoded as JSON
+func (s *Server) OpenAPI() map[string]interface{} {
+	schemas := make(map[string]interface{})
+	errorSchema := schemaFor(reflect.TypeOf(ErrorResponse{}), schemas)
+
+	paths := make(map[string]map[string]interface{})
+	for _, rt := range s.routes {
+		operation := map[string]interface{}{
+			"operationId": rt.operationID,
+			"summary":     rt.summary,
+			"responses": map[string]interface{}{
+				"200":     jsonContent("OK", schemaFor(reflect.TypeOf(rt.response), schemas)),
+				"default": jsonContent("Error", errorSchema),
+			},
+		}
+
+		if len(rt.params) > 0 {
+			var parameters []interface{}
+			for _, p := range rt.params {
+				parameters = append(parameters, map[string]interface{}{
+					"name":        p.name,
+					"in":          p.in,
+					"description": p.description,
+					"required":    p.required,
+					"schema":      schemaFor(reflect.TypeOf(p.schema), schemas),
+				})
+			}
+			operation["parameters"] = parameters
+		}
+
+		if rt.body != nil {
+			body := jsonContent("", schemaFor(reflect.TypeOf(rt.body), schemas))
+			delete(body, "description")
+			body["required"] = true
+			operation["requestBody"] = body
+		}
+
+		if paths[rt.path] == nil {
+			paths[rt.path] = make(map[string]interface{})
+		}
+		paths[rt.path][strings.ToLower(rt.method)] = operation
+	}
+
+	paths[specPath] = map[string]interface{}{
+		"get": map[string]interface{}{
+			"operationId": "getOpenAPI",
+			"summary":     "Get this OpenAPI document",
+			"responses": map[string]interface{}{
+				"200": jsonContent("OK", map[string]interface{}{"type": "object"}),
+			},
+		},
+	}
+
+	return map[string]interface{}{
+		"openapi": OpenAPIVersion,
+		"info": map[string]interface{}{
+			"title":       "Zonne API",
+			"version":     APIVersion,
+			"description": "Zonne energy marketplace state and unsigned transactions for client-side signing",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+// jsonContent describes a JSON request or response body
+func jsonContent(description string, schema interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+// pathParam describes a path parameter of the type of schema
+func pathParam(name, description string, schema interface{}) param {
+	return param{name: name, in: "path", description: description, required: true, schema: schema}
+}
+
+// queryParam describes a query parameter of the type of schema
+func queryParam(name, description string, required bool, schema interface{}) param {
+	return param{name: name, in: "query", description: description, required: required, schema: schema}
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+	energyType        = reflect.TypeOf(zonnegosdk.EnergyType(0))
+	energyQuantity    = reflect.TypeOf(zonnegosdk.Energy(0))
+	base58Types       = map[reflect.Type]string{
+		reflect.TypeOf(solana.PublicKey{}): "base58 public key",
+		reflect.TypeOf(solana.Signature{}): "base58 signature",
+		reflect.TypeOf(solana.Hash{}):      "base58 hash",
+	}
+)
+
+// schemaFor returns the JSON schema of a Go type as encoded by encoding/json.
+// Structs are added to schemas and referenced by name.
+func schemaFor(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if description, ok := base58Types[t]; ok {
+		return map[string]interface{}{"type": "string", "description": description}
+	}
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case energyType:
+		var names []string
+		for _, et := range zonnegosdk.EnergyTypes() {
+			name, _ := json.Marshal(et)
+			names = append(names, strings.Trim(string(name), `"`))
+		}
+		return map[string]interface{}{"type": "string", "enum": names}
+	case energyQuantity:
+		return map[string]interface{}{"type": "string", "description": "energy quantity with unit", "example": "1.5MWh"}
+	}
+	if t.Implements(textMarshalerType) {
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64", "minimum": 0}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+		ref := map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+		if _, ok := schemas[t.Name()]; ok {
+			return ref
+		}
+		schemas[t.Name()] = nil // reserve the name for recursive types
+
+		properties := make(map[string]interface{})
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, omitempty, ok := jsonField(field)
+			if !ok {
+				continue
+			}
+			property := schemaFor(field.Type, schemas)
+			if enum := field.Tag.Get("enum"); enum != "" {
+				property["enum"] = strings.Split(enum, ",")
+			}
+			properties[name] = property
+			if !omitempty {
+				required = append(required, name)
+			}
+		}
+
+		schema := map[string]interface{}{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		schemas[t.Name()] = schema
+		return ref
+	}
+
+	return map[string]interface{}{}
+}
+
+// jsonField returns the JSON name of an exported struct field and whether it
+// is omitted when empty
+func jsonField(field reflect.StructField) (string, bool, bool) {
+	if field.PkgPath != "" {
+		return "", false, false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(options, "omitempty"), true
+}
@@ -0,0 +1,233 @@
+// Package server exposes the Zonne SDK as a JSON HTTP API for clients that
+// cannot use Go.
+//
+// GET endpoints return grid, producer, consumer, listing and mint record
+// state and the order book. POST endpoints under /v1/transactions build
+// unsigned mint, list, buy and cancel transactions for signing in a wallet,
+// encoded as base64 or base58, and /v1/transactions/submit sends a signed
+// transaction. /v1/solana-pay/listings/{listing} is a Solana Pay transaction
+// request that lets a wallet buy a listing by scanning a QR code. The OpenAPI document describing every route is served at
+// /openapi.json and is generated from the same route table as the handlers.
+//
+//	client, err := zonnegosdk.NewClientFromProfile(zonnegosdk.DevnetProfile())
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	log.Fatal(http.ListenAndServe(":8080", server.New(client)))
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// maxBodyBytes limits the size of request bodies
+const maxBodyBytes = 64 << 10
+
+// Server is an http.Handler serving the Zonne API
+type Server struct {
+	client    *zonnegosdk.Client
+	solanaPay SolanaPayConfig
+	routes    []route
+	logger    *log.Logger
+}
+
+// WithLogger sets the logger responses that fail to be written are reported
+// to. Defaults to the standard logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// New creates a server backed by the client
+func New(client *zonnegosdk.Client, opts ...Option) *Server {
+	s := &Server{client: client, logger: log.Default()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.routes = append(s.routeTable(), s.solanaPayRoutes()...)
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Browsers and Solana Pay wallets call the API cross-origin
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if r.URL.Path == specPath {
+		if r.Method != http.MethodGet {
+			s.writeError(w, &apiError{status: http.StatusMethodNotAllowed, message: "method not allowed"})
+			return
+		}
+		spec, err := json.MarshalIndent(s.OpenAPI(), "", "  ")
+		if err != nil {
+			s.writeError(w, &apiError{status: http.StatusInternalServerError, message: fmt.Sprintf("failed to encode OpenAPI document: %v", err)})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(spec); err != nil {
+			s.logger.Printf("failed to write OpenAPI document: %v", err)
+		}
+		return
+	}
+
+	pathMatched := false
+	for _, rt := range s.routes {
+		params, ok := rt.match(r.URL.Path)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if rt.method != r.Method {
+			continue
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+		result, err := rt.handle(&request{Request: r, params: params})
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		s.writeJSON(w, http.StatusOK, result)
+		return
+	}
+
+	if pathMatched {
+		s.writeError(w, &apiError{status: http.StatusMethodNotAllowed, message: "method not allowed"})
+		return
+	}
+	s.writeError(w, &apiError{status: http.StatusNotFound, message: "no route for " + r.URL.Path})
+}
+
+// request is an incoming request with its path parameters
+type request struct {
+	*http.Request
+	params map[string]string
+}
+
+// decode reads the JSON request body into v, rejecting unknown fields
+func (r *request) decode(v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return badRequest("invalid request body: %v", err)
+	}
+	return nil
+}
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Error  string                  `json:"error"`
+	Fields []zonnegosdk.FieldError `json:"fields,omitempty"`
+}
+
+// apiError is an error with an HTTP status
+type apiError struct {
+	status  int
+	message string
+}
+
+// Error implements the error interface
+func (e *apiError) Error() string {
+	return e.message
+}
+
+// badRequest returns a 400 error
+func badRequest(format string, args ...interface{}) error {
+	return &apiError{status: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+// writeError maps an error to a status code and writes it: validation errors
+// and malformed input are 400, missing accounts 404, seed collisions 409 and
+// anything else, such as RPC failures, 502
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	response := ErrorResponse{Error: err.Error()}
+	status := http.StatusBadGateway
+
+	var apiErr *apiError
+	var validationErr *zonnegosdk.ValidationError
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.status
+	case errors.As(err, &validationErr):
+		status = http.StatusBadRequest
+		response.Fields = validationErr.Fields
+	case errors.Is(err, rpc.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, zonnegosdk.ErrSeedCollision):
+		status = http.StatusConflict
+	}
+
+	s.writeJSON(w, status, response)
+}
+
+// writeJSON writes v as a JSON response, logging a failure to encode or send it
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.logger.Printf("failed to write %d response: %v", status, err)
+	}
+}
+
+// route is an API endpoint. The OpenAPI document is generated from the
+// route's metadata, so every route must describe its parameters, body and
+// response.
+type route struct {
+	method      string
+	path        string
+	operationID string
+	summary     string
+	params      []param
+	body        interface{}
+	response    interface{}
+	handle      func(r *request) (interface{}, error)
+}
+
+// param describes a path or query parameter
+type param struct {
+	name        string
+	in          string
+	description string
+	required    bool
+	schema      interface{}
+}
+
+// match matches a URL path against the route's path template, returning the
+// values of its {name} segments
+func (rt route) match(path string) (map[string]string, bool) {
+	template := strings.Split(strings.Trim(rt.path, "/"), "/")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(template) != len(segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, segment := range template {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[segment[1:len(segment)-1]] = segments[i]
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
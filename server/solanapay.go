@@ -0,0 +1,196 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/akbariandev/zonnegosdk"
+	"github.com/gagliardetto/solana-go"
+)
+
+// Solana Pay transaction requests let a wallet buy a listing by scanning a
+// QR code. The wallet GETs the link for a label and icon, then POSTs its
+// account and receives a BuyTokens transaction to sign and send. See
+// https://docs.solanapay.com/spec#specification-transaction-request.
+
+// solanaPayPath is the path template of the transaction request link
+const solanaPayPath = "/v1/solana-pay/listings/{listing}"
+
+// DefaultSolanaPayLabel is the label shown by wallets when none is configured
+const DefaultSolanaPayLabel = "Zonne Energy"
+
+// SolanaPayConfig configures the Solana Pay transaction request endpoint
+type SolanaPayConfig struct {
+	// Label is shown by the wallet as the requester's name
+	Label string
+	// Icon is the URL of an SVG, PNG or WebP icon shown by the wallet
+	Icon string
+	// ConsumerAuthority, when set, initializes and pays for the consumer
+	// account of buyers that do not have one, and partially signs the
+	// transaction. Otherwise the buyer's wallet is the authority.
+	ConsumerAuthority solana.PrivateKey
+}
+
+// Option configures optional Server behaviour
+type Option func(*Server)
+
+// WithSolanaPay configures the Solana Pay transaction request endpoint
+func WithSolanaPay(config SolanaPayConfig) Option {
+	return func(s *Server) {
+		s.solanaPay = config
+	}
+}
+
+// SolanaPayMetadata is the response to the wallet's GET request
+type SolanaPayMetadata struct {
+	Label string `json:"label"`
+	Icon  string `json:"icon,omitempty"`
+}
+
+// SolanaPayRequest is the body of the wallet's POST request
+type SolanaPayRequest struct {
+	Account string `json:"account"`
+}
+
+// SolanaPayResponse is the transaction returned to the wallet, base64
+// encoded with zeroed signatures for the signers the wallet must fill in
+type SolanaPayResponse struct {
+	Transaction string `json:"transaction"`
+	Message     string `json:"message,omitempty"`
+}
+
+// solanaPayRoutes lists the Solana Pay endpoints
+func (s *Server) solanaPayRoutes() []route {
+	listing := pathParam("listing", "listing reference", "")
+	return []route{
+		{
+			method: http.MethodGet, path: solanaPayPath, operationID: "getSolanaPayMetadata",
+			summary:  "Solana Pay transaction request: label and icon shown by the wallet",
+			params:   []param{listing},
+			response: SolanaPayMetadata{},
+			handle:   s.solanaPayMetadata,
+		},
+		{
+			method: http.MethodPost, path: solanaPayPath, operationID: "buildSolanaPayTransaction",
+			summary:  "Solana Pay transaction request: buy the listing with the wallet's account, initializing its consumer account if missing",
+			params:   []param{listing},
+			body:     SolanaPayRequest{},
+			response: SolanaPayResponse{},
+			handle:   s.solanaPayTransaction,
+		},
+	}
+}
+
+func (s *Server) solanaPayMetadata(r *request) (interface{}, error) {
+	if _, err := s.listingRef(r.params["listing"]); err != nil {
+		return nil, err
+	}
+	label := s.solanaPay.Label
+	if label == "" {
+		label = DefaultSolanaPayLabel
+	}
+	return SolanaPayMetadata{Label: label, Icon: s.solanaPay.Icon}, nil
+}
+
+func (s *Server) solanaPayTransaction(r *request) (interface{}, error) {
+	// Wallets may send fields beyond account, so unknown fields are allowed
+	var body SolanaPayRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, badRequest("invalid request body: %v", err)
+	}
+	buyer, err := publicKeyParam("account", body.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := s.activeListing(r.Context(), r.params["listing"])
+	if err != nil {
+		return nil, err
+	}
+
+	authority := buyer
+	var signers []solana.PrivateKey
+	if s.solanaPay.ConsumerAuthority != nil {
+		authority = s.solanaPay.ConsumerAuthority.PublicKey()
+	}
+	plan, err := s.client.PlanPurchase(r.Context(), buyer, ref, authority)
+	if err != nil {
+		return nil, err
+	}
+	if plan.InitializesConsumer && s.solanaPay.ConsumerAuthority != nil {
+		signers = append(signers, s.solanaPay.ConsumerAuthority)
+	}
+
+	transaction, _, err := s.client.BuildUnsignedTransaction(r.Context(), buyer, plan.Instructions...)
+	if err != nil {
+		return nil, err
+	}
+	if err := zonnegosdk.PartialSignTransaction(transaction, signers...); err != nil {
+		return nil, err
+	}
+	data, err := transaction.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize transaction: %w", err)
+	}
+
+	return SolanaPayResponse{
+		Transaction: base64.StdEncoding.EncodeToString(data),
+		Message:     solanaPayMessage(ref),
+	}, nil
+}
+
+// solanaPayMessage describes the purchase for the wallet
+func solanaPayMessage(ref zonnegosdk.ListingRef) string {
+	energy, err := zonnegosdk.EnergyFromTokens(ref.Seeds.Amount)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("Buy %s of %s energy for %s SOL", energy, ref.Seeds.EnergyType, zonnegosdk.FormatSOL(ref.Seeds.PriceLamports))
+}
+
+// SolanaPayLink returns the HTTPS link of the transaction request for a
+// listing on a gateway served at baseURL, e.g. "https://api.example.com"
+func SolanaPayLink(baseURL string, ref zonnegosdk.ListingRef) (string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL: %w", err)
+	}
+	if base.Scheme != "https" {
+		return "", fmt.Errorf("invalid base URL %q: Solana Pay requires https", baseURL)
+	}
+
+	path := strings.Replace(solanaPayPath, "{listing}", url.PathEscape(ref.String()), 1)
+	return strings.TrimSuffix(base.String(), "/") + path, nil
+}
+
+// SolanaPayURL returns the solana: URL of a transaction request link, the
+// payload to encode in a QR code. The link is URL-encoded when it has a
+// query, as the spec requires.
+func SolanaPayURL(link string) (string, error) {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid link: %w", err)
+	}
+	if parsed.Scheme != "https" {
+		return "", fmt.Errorf("invalid link %q: Solana Pay requires https", link)
+	}
+
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		link = url.QueryEscape(link)
+	}
+	return "solana:" + link, nil
+}
+
+// SolanaPayListingURL returns the solana: URL that buys a listing through the
+// gateway served at baseURL
+func SolanaPayListingURL(baseURL string, ref zonnegosdk.ListingRef) (string, error) {
+	link, err := SolanaPayLink(baseURL, ref)
+	if err != nil {
+		return "", err
+	}
+	return SolanaPayURL(link)
+}
@@ -0,0 +1,146 @@
+package zonnegosdk
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// LamportsPerSOL is the number of lamports in one SOL
+const LamportsPerSOL = uint64(1_000_000_000)
+
+// RoundingMode controls how values with more decimal places than the target
+// precision are handled
+type RoundingMode int
+
+const (
+	// RoundExact rejects values that cannot be represented exactly
+	RoundExact RoundingMode = iota
+	// RoundDown truncates toward zero
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+	// RoundHalfUp rounds to nearest, ties away from zero
+	RoundHalfUp
+	// RoundHalfEven rounds to nearest, ties to even
+	RoundHalfEven
+)
+
+// ParseSOL parses a decimal SOL amount such as "1.000000001" into lamports.
+// It fails on overflow or when the amount has more than nine decimal places.
+func ParseSOL(s string) (uint64, error) {
+	return ParseSOLWithRounding(s, RoundExact)
+}
+
+// ParseSOLWithRounding parses a decimal SOL amount into lamports, rounding
+// excess decimal places with the given mode
+func ParseSOLWithRounding(s string, mode RoundingMode) (uint64, error) {
+	lamports, err := parseDecimal(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "SOL")), LamportsPerSOL, mode)
+	if err != nil {
+		return 0, fmt.Errorf("invalid SOL amount %q: %w", s, err)
+	}
+	return lamports, nil
+}
+
+// FormatSOL formats lamports as an exact decimal SOL amount, e.g. "1.000000001"
+func FormatSOL(lamports uint64) string {
+	return formatDecimal(lamports/LamportsPerSOL, lamports%LamportsPerSOL, LamportsPerSOL)
+}
+
+// formatDecimal formats whole + frac/scale with trailing zeros removed.
+// scale must be a power of ten.
+func formatDecimal(whole, frac, scale uint64) string {
+	s := strconv.FormatUint(whole, 10)
+	if frac == 0 {
+		return s
+	}
+
+	digits := len(strconv.FormatUint(scale, 10)) - 1
+	fracStr := strconv.FormatUint(frac, 10)
+	fracStr = strings.Repeat("0", digits-len(fracStr)) + fracStr
+	return s + "." + strings.TrimRight(fracStr, "0")
+}
+
+// parseDecimal parses a non-negative decimal string and multiplies it by
+// scale, a power of ten, rounding excess decimal places with the given mode.
+// It fails on overflow.
+func parseDecimal(s string, scale uint64, mode RoundingMode) (uint64, error) {
+	wholeStr, fracStr, hasFrac := strings.Cut(s, ".")
+	if wholeStr == "" && fracStr == "" {
+		return 0, fmt.Errorf("missing value")
+	}
+	if hasFrac && fracStr == "" {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	if !isDigits(wholeStr) || !isDigits(fracStr) {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	if wholeStr == "" {
+		wholeStr = "0"
+	}
+
+	whole, err := strconv.ParseUint(wholeStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("value overflows")
+	}
+
+	hi, value := bits.Mul64(whole, scale)
+	if hi != 0 {
+		return 0, fmt.Errorf("value overflows")
+	}
+
+	decimals := len(strconv.FormatUint(scale, 10)) - 1
+	kept, excess := fracStr, ""
+	if len(fracStr) > decimals {
+		kept, excess = fracStr[:decimals], fracStr[decimals:]
+	}
+	if kept != "" {
+		frac, err := strconv.ParseUint(kept+strings.Repeat("0", decimals-len(kept)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", s)
+		}
+		var carry uint64
+		if value, carry = bits.Add64(value, frac, 0); carry != 0 {
+			return 0, fmt.Errorf("value overflows")
+		}
+	}
+
+	if strings.Trim(excess, "0") == "" {
+		return value, nil
+	}
+
+	roundUp := false
+	switch mode {
+	case RoundExact:
+		return 0, fmt.Errorf("too many decimal places in %q", s)
+	case RoundDown:
+	case RoundUp:
+		roundUp = true
+	case RoundHalfUp:
+		roundUp = excess[0] >= '5'
+	case RoundHalfEven:
+		tie := excess[0] == '5' && strings.Trim(excess[1:], "0") == ""
+		roundUp = excess[0] > '5' || (excess[0] == '5' && !tie) || (tie && value%2 == 1)
+	default:
+		return 0, fmt.Errorf("unsupported rounding mode %d", mode)
+	}
+
+	if roundUp {
+		if value == ^uint64(0) {
+			return 0, fmt.Errorf("value overflows")
+		}
+		value++
+	}
+	return value, nil
+}
+
+// isDigits reports whether s consists only of ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package zonnegosdk
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseSOLWithRounding(t *testing.T) {
+	tests := []struct {
+		input string
+		mode  RoundingMode
+		want  uint64
+		fails bool
+	}{
+		{"1", RoundExact, LamportsPerSOL, false},
+		{"1.000000001", RoundExact, LamportsPerSOL + 1, false},
+		{".5", RoundExact, LamportsPerSOL / 2, false},
+		{"2 SOL", RoundExact, 2 * LamportsPerSOL, false},
+		{"0.0000000010", RoundExact, 1, false},
+		{"", RoundExact, 0, true},
+		{"1.", RoundExact, 0, true},
+		{"-1", RoundExact, 0, true},
+		{"1e9", RoundExact, 0, true},
+
+		// Excess precision
+		{"0.0000000015", RoundExact, 0, true},
+		{"0.0000000015", RoundDown, 1, false},
+		{"0.0000000011", RoundUp, 2, false},
+		{"0.0000000014", RoundHalfUp, 1, false},
+		{"0.0000000016", RoundHalfEven, 2, false},
+
+		// Ties
+		{"0.0000000015", RoundHalfUp, 2, false},
+		{"0.0000000025", RoundHalfUp, 3, false},
+		{"0.0000000015", RoundHalfEven, 2, false},
+		{"0.0000000025", RoundHalfEven, 2, false},
+		{"0.00000000250", RoundHalfEven, 2, false},
+		{"0.00000000251", RoundHalfEven, 3, false},
+		{"0.0000000025", RoundDown, 2, false},
+		{"0.0000000025", RoundUp, 3, false},
+		{"0.0000000005", RoundHalfEven, 0, false},
+		{"0.0000000005", RoundHalfUp, 1, false},
+
+		// Overflow near MaxUint64
+		{"18446744073.709551615", RoundExact, math.MaxUint64, false},
+		{"18446744073.709551616", RoundExact, 0, true},
+		{"18446744074", RoundExact, 0, true},
+		{"18446744073709551616", RoundExact, 0, true},
+		{"18446744073.7095516154", RoundDown, math.MaxUint64, false},
+		{"18446744073.7095516154", RoundHalfUp, math.MaxUint64, false},
+		{"18446744073.7095516155", RoundHalfUp, 0, true},
+		{"18446744073.7095516151", RoundUp, 0, true},
+	}
+	for _, test := range tests {
+		got, err := ParseSOLWithRounding(test.input, test.mode)
+		if test.fails {
+			if err == nil {
+				t.Errorf("ParseSOLWithRounding(%q, %d) = %d, want an error", test.input, test.mode, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSOLWithRounding(%q, %d): %v", test.input, test.mode, err)
+		} else if got != test.want {
+			t.Errorf("ParseSOLWithRounding(%q, %d) = %d, want %d", test.input, test.mode, got, test.want)
+		}
+	}
+}
+
+func TestFormatSOLRoundTrip(t *testing.T) {
+	tests := []struct {
+		lamports uint64
+		want     string
+	}{
+		{0, "0"},
+		{1, "0.000000001"},
+		{LamportsPerSOL, "1"},
+		{LamportsPerSOL + 1, "1.000000001"},
+		{LamportsPerSOL / 2, "0.5"},
+		{1_230_000_000, "1.23"},
+		{math.MaxUint64, "18446744073.709551615"},
+	}
+	for _, test := range tests {
+		formatted := FormatSOL(test.lamports)
+		if formatted != test.want {
+			t.Errorf("FormatSOL(%d) = %q, want %q", test.lamports, formatted, test.want)
+		}
+		parsed, err := ParseSOL(formatted)
+		if err != nil {
+			t.Errorf("ParseSOL(%q): %v", formatted, err)
+		} else if parsed != test.lamports {
+			t.Errorf("ParseSOL(FormatSOL(%d)) = %d", test.lamports, parsed)
+		}
+	}
+}
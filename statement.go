@@ -0,0 +1,249 @@
+package zonnegosdk
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// StatementEntry is one event affecting a producer within a statement period
+type StatementEntry struct {
+	Time       time.Time         `json:"time"`
+	Signature  solana.Signature  `json:"signature"`
+	Event      string            `json:"event"`
+	ListingID  *solana.PublicKey `json:"listing_id,omitempty"`
+	EnergyType *EnergyType       `json:"energy_type,omitempty"`
+	Amount     uint64            `json:"amount"`
+	Change     int64             `json:"change"`
+	Lamports   uint64            `json:"lamports"`
+	Balance    int64             `json:"balance"`
+}
+
+// ProducerStatement summarises a producer's activity over a period. Token
+// balances are replayed from the producer's full event history and checked
+// against the on-chain ProducerAccount.Balance.
+type ProducerStatement struct {
+	Producer       solana.PublicKey      `json:"producer"`
+	From           time.Time             `json:"from"`
+	To             time.Time             `json:"to"`
+	OpeningBalance int64                 `json:"opening_balance"`
+	ClosingBalance int64                 `json:"closing_balance"`
+	Minted         map[EnergyType]Energy `json:"minted"`
+	Listed         Energy                `json:"listed"`
+	Cancelled      Energy                `json:"cancelled"`
+	Sold           Energy                `json:"sold"`
+	LamportsEarned uint64                `json:"lamports_earned"`
+	Entries        []StatementEntry      `json:"entries"`
+
+	// ReplayedBalance is the balance after replaying every known event;
+	// Reconciled reports whether it equals OnChainBalance
+	ReplayedBalance int64  `json:"replayed_balance"`
+	OnChainBalance  uint64 `json:"on_chain_balance"`
+	Reconciled      bool   `json:"reconciled"`
+}
+
+// GetProducerStatement builds the statement of a producer for events with a
+// block time in [from, to)
+func (c *Client) GetProducerStatement(ctx context.Context, producer solana.PublicKey, from, to time.Time) (*ProducerStatement, error) {
+	account, err := c.GetProducerAccount(ctx, producer)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := c.GetEventHistory(ctx, producer)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildProducerStatement(producer, from, to, history, account.Balance)
+}
+
+// BuildProducerStatement builds a producer statement from the producer's
+// event history, oldest first, and its current on-chain balance
+func BuildProducerStatement(producer solana.PublicKey, from, to time.Time, history []TransactionEvents, onChainBalance uint64) (*ProducerStatement, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("invalid statement period: %s is not after %s", to, from)
+	}
+
+	statement := &ProducerStatement{
+		Producer:       producer,
+		From:           from,
+		To:             to,
+		Minted:         make(map[EnergyType]Energy),
+		OnChainBalance: onChainBalance,
+	}
+
+	var balance int64
+	opened, closed := false, false
+	listingTypes := make(map[solana.PublicKey]EnergyType)
+	for _, tx := range history {
+		if !opened && !tx.BlockTime.Before(from) {
+			statement.OpeningBalance = balance
+			opened = true
+		}
+		if !closed && !tx.BlockTime.Before(to) {
+			statement.ClosingBalance = balance
+			closed = true
+		}
+
+		for _, event := range tx.Events {
+			entry, ok := producerStatementEntry(producer, event)
+			if !ok {
+				continue
+			}
+			if entry.ListingID != nil {
+				if entry.EnergyType != nil {
+					listingTypes[*entry.ListingID] = *entry.EnergyType
+				} else if energyType, ok := listingTypes[*entry.ListingID]; ok {
+					entry.EnergyType = &energyType
+				}
+			}
+			balance += entry.Change
+			entry.Balance = balance
+			entry.Time = tx.BlockTime
+			entry.Signature = tx.Signature
+
+			if tx.BlockTime.Before(from) || !tx.BlockTime.Before(to) {
+				continue
+			}
+			if err := statement.add(entry); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if !opened {
+		statement.OpeningBalance = balance
+	}
+	if !closed {
+		statement.ClosingBalance = balance
+	}
+	statement.ReplayedBalance = balance
+	statement.Reconciled = balance >= 0 && uint64(balance) == onChainBalance
+
+	return statement, nil
+}
+
+// producerStatementEntry converts an event concerning the producer to a
+// statement entry, with the change it made to the producer's token balance.
+// Cancellations and purchases do not carry the energy type; it is filled in
+// from the listing's TokensListed event during replay.
+func producerStatementEntry(producer solana.PublicKey, event *DecodedEvent) (StatementEntry, bool) {
+	entry := StatementEntry{Event: event.Name}
+
+	switch data := event.Data.(type) {
+	case *TokensMintedEvent:
+		if !data.Producer.Equals(producer) {
+			return entry, false
+		}
+		entry.EnergyType = &data.EnergyType
+		entry.Amount = data.Amount
+		entry.Change = int64(data.Amount)
+	case *TokensListedEvent:
+		if !data.Producer.Equals(producer) {
+			return entry, false
+		}
+		entry.ListingID = &data.ListingID
+		entry.EnergyType = &data.EnergyType
+		entry.Amount = data.Amount
+		entry.Change = -int64(data.Amount)
+	case *ListingCancelledEvent:
+		if !data.Producer.Equals(producer) {
+			return entry, false
+		}
+		entry.ListingID = &data.ListingID
+		entry.Amount = data.Amount
+		entry.Change = int64(data.Amount)
+	case *TokensPurchasedEvent:
+		if !data.Producer.Equals(producer) {
+			return entry, false
+		}
+		entry.ListingID = &data.ListingID
+		entry.Amount = data.Amount
+		entry.Lamports = data.PriceLamports
+	default:
+		return entry, false
+	}
+
+	return entry, true
+}
+
+// add records an entry that falls within the statement period
+func (s *ProducerStatement) add(entry StatementEntry) error {
+	energy, err := EnergyFromTokens(entry.Amount)
+	if err != nil {
+		return err
+	}
+
+	switch entry.Event {
+	case EventTokensMinted:
+		s.Minted[*entry.EnergyType] += energy
+	case EventTokensListed:
+		s.Listed += energy
+	case EventListingCancelled:
+		s.Cancelled += energy
+	case EventTokensPurchased:
+		s.Sold += energy
+		s.LamportsEarned += entry.Lamports
+	}
+
+	s.Entries = append(s.Entries, entry)
+	return nil
+}
+
+// TotalMinted returns the energy minted across all energy types
+func (s *ProducerStatement) TotalMinted() Energy {
+	var total Energy
+	for _, energy := range s.Minted {
+		total += energy
+	}
+	return total
+}
+
+// WriteJSON writes the statement as indented JSON
+func (s *ProducerStatement) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// WriteCSV writes the statement entries as CSV, one row per event
+func (s *ProducerStatement) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"time", "signature", "event", "listing_id", "energy_type", "amount", "change", "lamports", "balance"}); err != nil {
+		return err
+	}
+
+	for _, entry := range s.Entries {
+		listingID, energyType := "", ""
+		if entry.ListingID != nil {
+			listingID = entry.ListingID.String()
+		}
+		if entry.EnergyType != nil {
+			energyType = entry.EnergyType.String()
+		}
+
+		if err := writer.Write([]string{
+			entry.Time.Format(time.RFC3339),
+			entry.Signature.String(),
+			entry.Event,
+			listingID,
+			energyType,
+			strconv.FormatUint(entry.Amount, 10),
+			strconv.FormatInt(entry.Change, 10),
+			strconv.FormatUint(entry.Lamports, 10),
+			strconv.FormatInt(entry.Balance, 10),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
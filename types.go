@@ -1,6 +1,7 @@
 package zonnegosdk
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/gagliardetto/solana-go"
@@ -18,17 +19,17 @@ const (
 
 // GridAccount represents the grid state account
 type GridAccount struct {
-	IsActive bool `borsh:"is_active"`
+	IsActive bool `borsh:"is_active" json:"is_active"`
 }
 
 // ProducerAccount represents a producer's energy balance
 type ProducerAccount struct {
-	Balance uint64 `borsh:"balance"`
+	Balance uint64 `borsh:"balance" json:"balance"`
 }
 
 // ConsumerAccount represents a consumer's energy consumption
 type ConsumerAccount struct {
-	Consumption uint64 `borsh:"consumption"`
+	Consumption uint64 `borsh:"consumption" json:"consumption"`
 }
 
 // MintRecord represents a record of energy token minting
@@ -36,7 +37,7 @@ type MintRecord struct {
 	Grid        solana.PublicKey `borsh:"grid"`
 	Producer    solana.PublicKey `borsh:"producer"`
 	Amount      uint64           `borsh:"amount"`
-	EnergyType  uint8            `borsh:"energy_type"`
+	EnergyType  EnergyType       `borsh:"energy_type"`
 	Timestamp   int64            `borsh:"timestamp"`
 }
 
@@ -45,7 +46,7 @@ type ListingAccount struct {
 	Producer       solana.PublicKey `borsh:"producer"`
 	Amount         uint64           `borsh:"amount"`
 	PriceLamports  uint64           `borsh:"price_lamports"`
-	EnergyType     uint8            `borsh:"energy_type"`
+	EnergyType     EnergyType       `borsh:"energy_type"`
 	IsActive       bool             `borsh:"is_active"`
 	CreatedAt      int64            `borsh:"created_at"`
 }
@@ -71,7 +72,7 @@ type ConsumerInitializedEvent struct {
 type TokensMintedEvent struct {
 	Producer   solana.PublicKey `json:"producer"`
 	Amount     uint64           `json:"amount"`
-	EnergyType uint8            `json:"energy_type"`
+	EnergyType EnergyType       `json:"energy_type"`
 }
 
 // TokensListedEvent represents an energy token listing event
@@ -80,7 +81,7 @@ type TokensListedEvent struct {
 	Producer      solana.PublicKey `json:"producer"`
 	Amount        uint64           `json:"amount"`
 	PriceLamports uint64           `json:"price_lamports"`
-	EnergyType    uint8            `json:"energy_type"`
+	EnergyType    EnergyType       `json:"energy_type"`
 }
 
 // ListingCancelledEvent represents a listing cancellation event
@@ -123,21 +124,19 @@ func (e EnergyType) String() string {
 		return "Wind"
 	case EnergyTypeHydro:
 		return "Hydro"
-	default:
+	case EnergyTypeOther:
 		return "Other"
+	default:
+		return fmt.Sprintf("EnergyType(%d)", uint8(e))
 	}
 }
 
-// ParseEnergyType converts a string to EnergyType
+// ParseEnergyType converts a string to EnergyType, mapping unknown strings to
+// EnergyTypeOther. Use ParseEnergyTypeStrict to reject unknown strings.
 func ParseEnergyType(s string) EnergyType {
-	switch s {
-	case "Solar", "solar":
-		return EnergyTypeSolar
-	case "Wind", "wind":
-		return EnergyTypeWind
-	case "Hydro", "hydro":
-		return EnergyTypeHydro
-	default:
+	energyType, err := ParseEnergyTypeStrict(s)
+	if err != nil {
 		return EnergyTypeOther
 	}
+	return energyType
 }
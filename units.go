@@ -0,0 +1,229 @@
+package zonnegosdk
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Energy is a quantity of energy in watt-hours
+type Energy uint64
+
+// Energy units
+const (
+	WattHour     Energy = 1
+	KilowattHour Energy = 1000 * WattHour
+	MegawattHour Energy = 1000 * KilowattHour
+	GigawattHour Energy = 1000 * MegawattHour
+)
+
+// TokenUnit is the energy represented by one on-chain energy token
+const TokenUnit = KilowattHour
+
+// energyUnit is a named unit of energy
+type energyUnit struct {
+	suffix string
+	unit   Energy
+}
+
+// energyUnits lists the units used for formatting, largest first
+var energyUnits = []energyUnit{
+	{"GWh", GigawattHour},
+	{"MWh", MegawattHour},
+	{"kWh", KilowattHour},
+	{"Wh", WattHour},
+}
+
+// energyParseUnits lists the unit suffixes accepted by ParseEnergy
+var energyParseUnits = append([]energyUnit{{"KWh", KilowattHour}}, energyUnits...)
+
+// EnergyFromTokens converts an on-chain token amount to energy
+func EnergyFromTokens(amount uint64) (Energy, error) {
+	hi, lo := bits.Mul64(amount, uint64(TokenUnit))
+	if hi != 0 {
+		return 0, fmt.Errorf("energy overflow: %d tokens", amount)
+	}
+	return Energy(lo), nil
+}
+
+// Tokens converts the energy to an on-chain token amount. It fails if the
+// energy is not a whole number of tokens.
+func (e Energy) Tokens() (uint64, error) {
+	if e%TokenUnit != 0 {
+		return 0, fmt.Errorf("energy %s is not a whole number of %s tokens", e, TokenUnit)
+	}
+	return uint64(e / TokenUnit), nil
+}
+
+// WattHours returns the energy in Wh
+func (e Energy) WattHours() uint64 {
+	return uint64(e)
+}
+
+// In returns the energy as a whole number of the given unit and the remainder in Wh
+func (e Energy) In(unit Energy) (uint64, Energy) {
+	return uint64(e / unit), e % unit
+}
+
+// String formats the energy exactly in the largest unit it reaches, e.g. "1.5MWh"
+func (e Energy) String() string {
+	for _, u := range energyUnits {
+		if e >= u.unit || u.unit == WattHour {
+			return formatDecimal(uint64(e/u.unit), uint64(e%u.unit), uint64(u.unit)) + u.suffix
+		}
+	}
+	return "0Wh"
+}
+
+// ParseEnergy parses an energy quantity such as "1.5MWh", "250 kWh" or
+// "42Wh". The value must be a whole number of watt-hours.
+func ParseEnergy(s string) (Energy, error) {
+	trimmed := strings.TrimSpace(s)
+	for _, u := range energyParseUnits {
+		if !strings.HasSuffix(trimmed, u.suffix) {
+			continue
+		}
+		value, err := parseDecimal(strings.TrimSpace(strings.TrimSuffix(trimmed, u.suffix)), uint64(u.unit), RoundExact)
+		if err != nil {
+			return 0, fmt.Errorf("invalid energy %q: %w", s, err)
+		}
+		return Energy(value), nil
+	}
+	return 0, fmt.Errorf("invalid energy %q: unit must be one of Wh, kWh, MWh, GWh", s)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (e Energy) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (e *Energy) UnmarshalText(text []byte) error {
+	energy, err := ParseEnergy(string(text))
+	if err != nil {
+		return err
+	}
+	*e = energy
+	return nil
+}
+
+// Price is an amount of lamports for a quantity of energy
+type Price struct {
+	Lamports uint64
+	Per      Energy
+}
+
+// PricePerKWh returns a price of lamports per kWh
+func PricePerKWh(lamports uint64) Price {
+	return Price{Lamports: lamports, Per: KilowattHour}
+}
+
+// PriceForTotal returns the price of a whole listing: total lamports for the energy
+func PriceForTotal(totalLamports uint64, energy Energy) Price {
+	return Price{Lamports: totalLamports, Per: energy}
+}
+
+// Total returns the lamports due for the energy at this price. It fails on
+// overflow or when the result is not a whole number of lamports.
+func (p Price) Total(energy Energy) (uint64, error) {
+	if p.Per == 0 {
+		return 0, fmt.Errorf("price has no energy quantity")
+	}
+
+	hi, lo := bits.Mul64(p.Lamports, uint64(energy))
+	if hi >= uint64(p.Per) {
+		return 0, fmt.Errorf("price overflow: %d lamports per %s for %s", p.Lamports, p.Per, energy)
+	}
+
+	total, rem := bits.Div64(hi, lo, uint64(p.Per))
+	if rem != 0 {
+		return 0, fmt.Errorf("price of %s is not a whole number of lamports", energy)
+	}
+	return total, nil
+}
+
+// PerUnit returns the lamports per unit of energy, e.g. per KilowattHour.
+// It fails on overflow or when the result is not a whole number of lamports.
+func (p Price) PerUnit(unit Energy) (uint64, error) {
+	return p.Total(unit)
+}
+
+// String formats the price, e.g. "1000000 lamports/kWh" or "500 lamports/1.5MWh"
+func (p Price) String() string {
+	for _, u := range energyUnits {
+		if p.Per == u.unit {
+			return fmt.Sprintf("%d lamports/%s", p.Lamports, u.suffix)
+		}
+	}
+	return fmt.Sprintf("%d lamports/%s", p.Lamports, p.Per)
+}
+
+// NewListingParams builds listing parameters from an energy quantity and a
+// price, converting them to a token amount and the listing's total price
+func NewListingParams(producer solana.PublicKey, energy Energy, price Price, energyType EnergyType) (ListingAccountCreationParams, error) {
+	amount, err := energy.Tokens()
+	if err != nil {
+		return ListingAccountCreationParams{}, err
+	}
+
+	total, err := price.Total(energy)
+	if err != nil {
+		return ListingAccountCreationParams{}, err
+	}
+
+	params := ListingAccountCreationParams{
+		Producer:      producer,
+		Amount:        amount,
+		PriceLamports: total,
+		EnergyType:    energyType,
+	}
+	return params, ValidateListingParams(params)
+}
+
+// NewMintParams builds mint parameters from an energy quantity
+func NewMintParams(grid, producer, gridAuthority solana.PublicKey, energy Energy, energyType EnergyType) (MintRecordCreationParams, error) {
+	amount, err := energy.Tokens()
+	if err != nil {
+		return MintRecordCreationParams{}, err
+	}
+
+	params := MintRecordCreationParams{
+		Grid:          grid,
+		Producer:      producer,
+		Amount:        amount,
+		EnergyType:    energyType,
+		GridAuthority: gridAuthority,
+	}
+	return params, ValidateMintParams(params)
+}
+
+// Energy returns the energy offered by the listing
+func (l *ListingAccount) Energy() (Energy, error) {
+	return EnergyFromTokens(l.Amount)
+}
+
+// Price returns the listing price: PriceLamports for the whole listing
+func (l *ListingAccount) Price() (Price, error) {
+	energy, err := l.Energy()
+	if err != nil {
+		return Price{}, err
+	}
+	return PriceForTotal(l.PriceLamports, energy), nil
+}
+
+// Energy returns the energy recorded by the mint record
+func (m *MintRecord) Energy() (Energy, error) {
+	return EnergyFromTokens(m.Amount)
+}
+
+// BalanceEnergy returns the producer's token balance as energy
+func (p *ProducerAccount) BalanceEnergy() (Energy, error) {
+	return EnergyFromTokens(p.Balance)
+}
+
+// ConsumptionEnergy returns the consumer's consumption as energy
+func (c *ConsumerAccount) ConsumptionEnergy() (Energy, error) {
+	return EnergyFromTokens(c.Consumption)
+}
@@ -0,0 +1,219 @@
+package zonnegosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// FieldError describes a single parameter that failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError lists every parameter of an operation that failed validation
+type ValidationError struct {
+	Op     string       `json:"op"`
+	Fields []FieldError `json:"fields"`
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+	return fmt.Sprintf("invalid %s parameters: %s", e.Op, strings.Join(messages, "; "))
+}
+
+// Has reports whether the named field failed validation
+func (e *ValidationError) Has(field string) bool {
+	for _, f := range e.Fields {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// validator collects field errors for an operation
+type validator struct {
+	op     string
+	fields []FieldError
+}
+
+func newValidator(op string) *validator {
+	return &validator{op: op}
+}
+
+func (v *validator) add(field, format string, args ...interface{}) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) publicKey(field string, key solana.PublicKey) {
+	if !ValidatePublicKey(key) {
+		v.add(field, "public key cannot be zero")
+	}
+}
+
+func (v *validator) privateKey(field string, key solana.PrivateKey) {
+	if len(key) != 64 {
+		v.add(field, "must be a 64-byte private key, got %d bytes", len(key))
+	}
+}
+
+func (v *validator) amount(field string, amount uint64) {
+	if !ValidateAmount(amount) {
+		v.add(field, "must be between %d and %d, got %d", MinEnergyAmount, MaxEnergyAmount, amount)
+	}
+}
+
+func (v *validator) price(field string, priceLamports uint64) {
+	if !ValidatePrice(priceLamports) {
+		v.add(field, "must be between %d and %d lamports, got %d", MinPriceLamports, MaxPriceLamports, priceLamports)
+	}
+}
+
+func (v *validator) energyType(field string, energyType EnergyType) {
+	if !IsValidEnergyType(energyType) {
+		v.add(field, "must be 0-%d, got %d", EnergyTypeOther, energyType)
+	}
+}
+
+// err returns a *ValidationError if any field failed, or nil
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Op: v.op, Fields: v.fields}
+}
+
+// ValidateGridParams checks the parameters of InitializeGrid
+func ValidateGridParams(params GridAccountCreationParams) error {
+	v := newValidator(InstructionInitializeGrid)
+	v.publicKey("Grid", params.Grid)
+	v.publicKey("Authority", params.Authority)
+	return v.err()
+}
+
+// ValidateProducerParams checks the parameters of InitializeProducer
+func ValidateProducerParams(params ProducerAccountCreationParams) error {
+	v := newValidator(InstructionInitializeProducer)
+	v.publicKey("Producer", params.Producer)
+	v.publicKey("Authority", params.Authority)
+	return v.err()
+}
+
+// ValidateConsumerParams checks the parameters of InitializeConsumer
+func ValidateConsumerParams(params ConsumerAccountCreationParams) error {
+	v := newValidator(InstructionInitializeConsumer)
+	v.publicKey("Consumer", params.Consumer)
+	v.publicKey("Authority", params.Authority)
+	return v.err()
+}
+
+// ValidateMintParams checks the parameters of MintEnergyTokens
+func ValidateMintParams(params MintRecordCreationParams) error {
+	v := newValidator(InstructionMintEnergyTokens)
+	v.publicKey("Grid", params.Grid)
+	v.publicKey("Producer", params.Producer)
+	v.publicKey("GridAuthority", params.GridAuthority)
+	v.amount("Amount", params.Amount)
+	v.energyType("EnergyType", params.EnergyType)
+	return v.err()
+}
+
+// ValidateListingParams checks the parameters of ListTokensForSale
+func ValidateListingParams(params ListingAccountCreationParams) error {
+	v := newValidator(InstructionListTokensForSale)
+	v.publicKey("Producer", params.Producer)
+	v.amount("Amount", params.Amount)
+	v.price("PriceLamports", params.PriceLamports)
+	v.energyType("EnergyType", params.EnergyType)
+	return v.err()
+}
+
+// validateListingSeeds checks the seeds identifying an existing listing
+func validateListingSeeds(op string, seeds ListingSeeds, buyer *solana.PublicKey) error {
+	v := newValidator(op)
+	if buyer != nil {
+		v.publicKey("Buyer", *buyer)
+	}
+	v.publicKey("Producer", seeds.Producer)
+	v.amount("Amount", seeds.Amount)
+	v.price("PriceLamports", seeds.PriceLamports)
+	v.energyType("EnergyType", seeds.EnergyType)
+	return v.err()
+}
+
+// ListingPriceForUnits returns the total listing price for amount tokens at
+// unitPriceLamports each. It fails if the product overflows or exceeds
+// MaxPriceLamports.
+func ListingPriceForUnits(amount, unitPriceLamports uint64) (uint64, error) {
+	v := newValidator(InstructionListTokensForSale)
+	v.amount("Amount", amount)
+	v.price("UnitPriceLamports", unitPriceLamports)
+	if err := v.err(); err != nil {
+		return 0, err
+	}
+
+	energy, err := EnergyFromTokens(amount)
+	if err != nil {
+		return 0, err
+	}
+	total, err := PricePerKWh(unitPriceLamports).Total(energy)
+	if err != nil || total > MaxPriceLamports {
+		v.add("PriceLamports", "%d tokens at %d lamports each exceeds %d lamports", amount, unitPriceLamports, MaxPriceLamports)
+		return 0, v.err()
+	}
+	return total, nil
+}
+
+// NewListingParamsPerUnit builds listing parameters from a per-token price
+func NewListingParamsPerUnit(producer solana.PublicKey, amount, unitPriceLamports uint64, energyType EnergyType) (ListingAccountCreationParams, error) {
+	energy, err := EnergyFromTokens(amount)
+	if err != nil {
+		return ListingAccountCreationParams{}, err
+	}
+	return NewListingParams(producer, energy, PricePerKWh(unitPriceLamports), energyType)
+}
+
+// ValidateListingBalance validates the listing parameters and additionally
+// checks that the producer's on-chain balance covers the listed amount
+func (c *Client) ValidateListingBalance(ctx context.Context, params ListingAccountCreationParams) error {
+	v := newValidator(InstructionListTokensForSale)
+
+	var verr *ValidationError
+	if err := ValidateListingParams(params); errors.As(err, &verr) {
+		if verr.Has("Producer") {
+			return err
+		}
+		v.fields = append(v.fields, verr.Fields...)
+	}
+
+	producer, err := c.GetProducerAccount(ctx, params.Producer)
+	if errors.Is(err, rpc.ErrNotFound) {
+		v.add("Producer", "has no producer account")
+		return v.err()
+	}
+	if err != nil {
+		if verr := v.err(); verr != nil {
+			return fmt.Errorf("failed to check producer balance: %w; %w", err, verr)
+		}
+		return fmt.Errorf("failed to check producer balance: %w", err)
+	}
+	if params.Amount > producer.Balance {
+		v.add("Amount", "%d exceeds producer balance of %d", params.Amount, producer.Balance)
+	}
+	return v.err()
+}
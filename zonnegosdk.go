@@ -15,7 +15,7 @@
 // Basic usage:
 //
 //	// Initialize client
-//	client := zonnegosdk.NewClient("http://localhost:8899")
+//	client, err := zonnegosdk.NewClientFromProfile(zonnegosdk.LocalnetProfile())
 //
 //	// Setup accounts
 //	gridParams := zonnegosdk.GridAccountCreationParams{
@@ -45,7 +45,11 @@
 // For more examples, see the examples/ directory.
 package zonnegosdk
 
-import "github.com/gagliardetto/solana-go"
+import (
+	"math"
+
+	"github.com/gagliardetto/solana-go"
+)
 
 // Version of the SDK
 const Version = "1.0.0"
@@ -81,8 +85,8 @@ const (
 // Common error messages
 const (
 	ErrInvalidPublicKey = "invalid public key: cannot be zero"
-	ErrInvalidAmount    = "invalid amount: must be greater than zero"
-	ErrInvalidPrice     = "invalid price: must be greater than zero"
+	ErrInvalidAmount    = "invalid amount: must be between MinEnergyAmount and MaxEnergyAmount"
+	ErrInvalidPrice     = "invalid price: must be between MinPriceLamports and MaxPriceLamports"
 	ErrInvalidEnergyType = "invalid energy type: must be 0-3"
 )
 
@@ -93,12 +97,14 @@ func IsZeroPublicKey(key solana.PublicKey) bool {
 	return key.IsZero()
 }
 
-// LamportsToSOL converts lamports to SOL
+// LamportsToSOL converts lamports to SOL. Large balances lose precision in
+// float64; use FormatSOL for exact output.
 func LamportsToSOL(lamports uint64) float64 {
 	return float64(lamports) / 1e9
 }
 
-// SOLToLamports converts SOL to lamports
+// SOLToLamports converts SOL to lamports, rounding to the nearest lamport.
+// Use ParseSOL to convert decimal strings exactly.
 func SOLToLamports(sol float64) uint64 {
-	return uint64(sol * 1e9)
+	return uint64(math.Round(sol * 1e9))
 }